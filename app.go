@@ -5,16 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	goruntime "runtime"
 	"strings"
+	"tempo/internal/calendar"
 	"tempo/internal/executor"
 	"tempo/internal/models"
 	"tempo/internal/notifier"
 	"tempo/internal/scheduler"
 	"tempo/internal/storage"
+	"tempo/internal/webhook"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,6 +31,7 @@ type App struct {
 	scheduler *scheduler.Scheduler
 	executor  *executor.Executor
 	notifier  *notifier.Notifier
+	webhook   *webhook.Server
 	dataDir   string
 }
 
@@ -62,29 +66,54 @@ func (a *App) startup(ctx context.Context) {
 		log.Fatal("Failed to initialize executor:", err)
 	}
 
-	// 初始化调度器
-	a.scheduler = scheduler.New(a.storage, a.executor)
+	// 将脚本实时输出以事件形式推送到前端，事件名按运行ID区分
+	a.executor.SetOutputHandler(func(event executor.OutputEvent) {
+		runtime.EventsEmit(a.ctx, "run:output:"+event.RunID, event)
+	})
 
 	// 初始化通知器
 	a.notifier = notifier.New()
 	a.notifier.SetConfigs(a.storage.GetAllNotifierConfigs())
 
+	// 初始化调度器
+	a.scheduler = scheduler.New(a.storage, a.executor)
+	a.scheduler.SetNotifyFunc(a.notify)
+
 	// 启动调度器
 	if err := a.scheduler.Start(); err != nil {
 		log.Printf("Failed to start scheduler: %v", err)
 	}
 
+	// 启动 Webhook 服务
+	a.webhook = webhook.New(a.storage, a.scheduler)
+	if settings := a.storage.GetSettings(); settings.EnableWebhooks {
+		if err := a.webhook.Start(settings.WebhookAddr); err != nil {
+			log.Printf("Failed to start webhook server: %v", err)
+		}
+	}
+
 	log.Println("Tempo started successfully")
 }
 
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
+	if a.webhook != nil {
+		a.webhook.Stop()
+	}
 	if a.scheduler != nil {
 		a.scheduler.Stop()
 	}
 	log.Println("Tempo shutdown")
 }
 
+// notify 发送通知（受全局通知开关控制）
+func (a *App) notify(taskLog *models.TaskLog) {
+	if !a.storage.GetSettings().EnableNotifications {
+		return
+	}
+	a.notifier.Notify(taskLog)
+}
+
 // GetAllTasks 获取所有任务
 func (a *App) GetAllTasks() []*models.Task {
 	return a.storage.GetAllTasks()
@@ -102,6 +131,19 @@ func (a *App) CreateTask(task *models.Task) error {
 	task.CreatedAt = now
 	task.UpdatedAt = now
 	task.Status = models.TaskStatusInactive
+	task.CompletedAt = nil
+	task.RunCount = 0
+	task.Webhook.Token = ""
+	if task.Webhook.Enabled {
+		task.Webhook.Token = webhook.NewToken()
+	}
+	if task.NotifyOn == "" {
+		task.NotifyOn = models.NotifyOnFailure
+	}
+
+	if err := a.validateTask(task); err != nil {
+		return err
+	}
 
 	if err := a.storage.SaveTask(task); err != nil {
 		return err
@@ -122,6 +164,47 @@ func (a *App) UpdateTask(task *models.Task) error {
 	task.CreatedAt = oldTask.CreatedAt
 	task.UpdatedAt = time.Now()
 
+	// 执行次数由调度器维护，修改次数上限后重新计数
+	task.RunCount = oldTask.RunCount
+	if task.MaxRuns != oldTask.MaxRuns {
+		task.RunCount = 0
+	}
+
+	// 一次性任务修改执行时间后可以重新执行
+	task.CompletedAt = oldTask.CompletedAt
+	if task.RunAt != nil && (oldTask.RunAt == nil || !task.RunAt.Equal(*oldTask.RunAt)) {
+		task.CompletedAt = nil
+	}
+
+	// 未传下游任务时保留原有触发链
+	if task.OnSuccess == nil {
+		task.OnSuccess = oldTask.OnSuccess
+	}
+	if task.OnFailure == nil {
+		task.OnFailure = oldTask.OnFailure
+	}
+	if task.OnComplete == nil {
+		task.OnComplete = oldTask.OnComplete
+	}
+
+	// 未指定通知策略时保留原策略，与创建时一样默认失败时通知
+	if task.NotifyOn == "" {
+		task.NotifyOn = oldTask.NotifyOn
+	}
+	if task.NotifyOn == "" {
+		task.NotifyOn = models.NotifyOnFailure
+	}
+
+	// Webhook 密钥只能通过 RegenerateWebhookToken 修改
+	task.Webhook.Token = oldTask.Webhook.Token
+	if task.Webhook.Enabled && task.Webhook.Token == "" {
+		task.Webhook.Token = webhook.NewToken()
+	}
+
+	if err := a.validateTask(task); err != nil {
+		return err
+	}
+
 	// 保存任务
 	if err := a.storage.SaveTask(task); err != nil {
 		return err
@@ -141,16 +224,150 @@ func (a *App) DeleteTask(id string) error {
 		log.Printf("Failed to remove task from scheduler: %v", err)
 	}
 
+	// 移除其他任务对该任务的下游引用
+	for _, task := range a.storage.GetAllTasks() {
+		if task.ID == id {
+			continue
+		}
+		onSuccess := removeID(task.OnSuccess, id)
+		onFailure := removeID(task.OnFailure, id)
+		onComplete := removeID(task.OnComplete, id)
+		if len(onSuccess) == len(task.OnSuccess) && len(onFailure) == len(task.OnFailure) && len(onComplete) == len(task.OnComplete) {
+			continue
+		}
+		task.OnSuccess, task.OnFailure, task.OnComplete = onSuccess, onFailure, onComplete
+		if err := a.storage.SaveTask(task); err != nil {
+			log.Printf("Failed to update downstream references of task %s: %v", task.Name, err)
+		}
+	}
+
 	return a.storage.DeleteTask(id)
 }
 
+// validateTask 校验任务配置并根据时间配置生成 cron 表达式，创建和更新任务共用
+func (a *App) validateTask(task *models.Task) error {
+	if err := validateTimezone(task.Timezone); err != nil {
+		return err
+	}
+	if err := a.validateCalendars(task.Calendars); err != nil {
+		return err
+	}
+	if task.Jitter < 0 {
+		return fmt.Errorf("jitter must not be negative")
+	}
+	if task.Watchdog.GracePeriod < 0 || task.Watchdog.ExpectedDuration < 0 {
+		return fmt.Errorf("watchdog durations must not be negative")
+	}
+	if err := validateWindow(task); err != nil {
+		return err
+	}
+	if err := a.validateConditions(task.Conditions); err != nil {
+		return err
+	}
+	if err := scheduler.ValidateParams(task.Params, task.ParamMode); err != nil {
+		return err
+	}
+	if err := a.validateWorkflow(task.Workflow); err != nil {
+		return err
+	}
+	cronExpr, err := scheduler.BuildCron(task)
+	if err != nil {
+		return err
+	}
+	task.Cron = cronExpr
+
+	// 校验下游任务引用，避免形成触发环
+	return scheduler.ValidateChain(a.storage.GetAllTasks(), task)
+}
+
+// validateTimezone 校验任务时区
+func validateTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return nil
+}
+
+// validateWindow 校验任务的生效期和最多执行次数
+func validateWindow(task *models.Task) error {
+	if task.StartAt != nil && task.EndAt != nil && !task.EndAt.After(*task.StartAt) {
+		return fmt.Errorf("end time must be after start time")
+	}
+	if task.MaxRuns < 0 {
+		return fmt.Errorf("max runs must not be negative")
+	}
+	return nil
+}
+
+// validateCalendars 校验任务引用的排除日历是否存在
+func (a *App) validateCalendars(ids []string) error {
+	for _, id := range ids {
+		if _, err := a.storage.GetCalendar(id); err != nil {
+			return fmt.Errorf("calendar %s not found", id)
+		}
+	}
+	return nil
+}
+
+// validateConditions 校验任务的前置条件及其引用的检查脚本
+func (a *App) validateConditions(conditions []models.Condition) error {
+	for _, cond := range conditions {
+		if err := scheduler.ValidateCondition(cond); err != nil {
+			return err
+		}
+		if cond.Type == models.ConditionScript {
+			if _, err := a.storage.GetScript(cond.ScriptID); err != nil {
+				return fmt.Errorf("check script %s not found", cond.ScriptID)
+			}
+		}
+	}
+	return nil
+}
+
+// validateWorkflow 校验工作流步骤及其引用的脚本
+func (a *App) validateWorkflow(wf *models.Workflow) error {
+	if err := scheduler.ValidateWorkflow(wf); err != nil {
+		return err
+	}
+	if wf == nil {
+		return nil
+	}
+	for _, step := range wf.Steps {
+		if _, err := a.storage.GetScript(step.ScriptID); err != nil {
+			return fmt.Errorf("script %s of step %s not found", step.ScriptID, step.ID)
+		}
+	}
+	if wf.CleanupID != "" {
+		if _, err := a.storage.GetScript(wf.CleanupID); err != nil {
+			return fmt.Errorf("cleanup script %s not found", wf.CleanupID)
+		}
+	}
+	return nil
+}
+
+// removeID 从ID列表中移除指定ID
+func removeID(ids []string, id string) []string {
+	result := make([]string, 0, len(ids))
+	for _, v := range ids {
+		if v != id {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 // ToggleTaskStatus 切换任务状态
 func (a *App) ToggleTaskStatus(id string) error {
-	task, err := a.storage.GetTask(id)
+	current, err := a.storage.GetTask(id)
 	if err != nil {
 		return err
 	}
 
+	// 在副本上修改，加入调度失败时不影响已保存的任务
+	task := *current
 	if task.Status == models.TaskStatusActive {
 		task.Status = models.TaskStatusInactive
 		if err := a.scheduler.RemoveTask(id); err != nil {
@@ -158,13 +375,17 @@ func (a *App) ToggleTaskStatus(id string) error {
 		}
 	} else {
 		task.Status = models.TaskStatusActive
-		if err := a.scheduler.AddTask(task); err != nil {
+		if err := a.scheduler.AddTask(&task); err != nil {
 			return fmt.Errorf("failed to add task to scheduler: %w", err)
 		}
+		// 下次运行时间由调度器计算并保存在存储的任务上
+		if scheduled, err := a.storage.GetTask(id); err == nil {
+			task.NextRunAt = scheduled.NextRunAt
+		}
 	}
 
 	task.UpdatedAt = time.Now()
-	return a.storage.SaveTask(task)
+	return a.storage.SaveTask(&task)
 }
 
 // RunTaskNow 立即运行任务
@@ -172,6 +393,57 @@ func (a *App) RunTaskNow(id string) error {
 	return a.scheduler.RunTaskNow(id)
 }
 
+// RunTaskWithParams 使用指定参数立即运行任务，未指定的参数使用默认值
+func (a *App) RunTaskWithParams(id string, params map[string]string) error {
+	return a.scheduler.RunTask(id, scheduler.RunOptions{Params: params})
+}
+
+// GetWebhookURL 获取任务的 Webhook 触发地址
+func (a *App) GetWebhookURL(id string) (string, error) {
+	task, err := a.storage.GetTask(id)
+	if err != nil {
+		return "", err
+	}
+	if !task.Webhook.Enabled || task.Webhook.Token == "" {
+		return "", fmt.Errorf("webhook is not enabled for task %s", task.Name)
+	}
+
+	addr := a.webhook.Addr()
+	if addr == "" {
+		addr = a.storage.GetSettings().WebhookAddr
+	}
+	return webhook.URL(addr, task), nil
+}
+
+// RegenerateWebhookToken 重新生成任务的 Webhook 密钥，旧地址立即失效，返回新的触发地址
+func (a *App) RegenerateWebhookToken(id string) (string, error) {
+	task, err := a.storage.GetTask(id)
+	if err != nil {
+		return "", err
+	}
+	if !task.Webhook.Enabled {
+		return "", fmt.Errorf("webhook is not enabled for task %s", task.Name)
+	}
+
+	task.Webhook.Token = webhook.NewToken()
+	task.UpdatedAt = time.Now()
+	if err := a.storage.SaveTask(task); err != nil {
+		return "", err
+	}
+	return a.GetWebhookURL(id)
+}
+
+// PauseScheduler 暂停所有定时执行，until 为空时需要手动恢复
+// 暂停状态在重启后保留，任务状态不变，立即运行不受影响
+func (a *App) PauseScheduler(until *time.Time) error {
+	return a.scheduler.Pause(until)
+}
+
+// ResumeScheduler 恢复定时执行
+func (a *App) ResumeScheduler() error {
+	return a.scheduler.Resume()
+}
+
 // GetTaskLogs 获取任务日志
 func (a *App) GetTaskLogs(taskID string, limit int) []*models.TaskLog {
 	if limit <= 0 {
@@ -242,6 +514,117 @@ func (a *App) DeleteNotifierConfig(id string) error {
 	return nil
 }
 
+// GetAllCalendars 获取所有排除日历
+func (a *App) GetAllCalendars() []*models.Calendar {
+	return a.storage.GetAllCalendars()
+}
+
+// CreateCalendar 创建排除日历
+func (a *App) CreateCalendar(cal *models.Calendar) error {
+	if err := cal.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cal.ID = uuid.New().String()
+	cal.CreatedAt = now
+	cal.UpdatedAt = now
+
+	return a.storage.SaveCalendar(cal)
+}
+
+// UpdateCalendar 更新排除日历
+func (a *App) UpdateCalendar(cal *models.Calendar) error {
+	oldCalendar, err := a.storage.GetCalendar(cal.ID)
+	if err != nil {
+		return err
+	}
+	if err := cal.Validate(); err != nil {
+		return err
+	}
+
+	cal.CreatedAt = oldCalendar.CreatedAt
+	cal.UpdatedAt = time.Now()
+
+	if err := a.storage.SaveCalendar(cal); err != nil {
+		return err
+	}
+
+	// 排除范围变化后重新计算下次运行时间
+	a.scheduler.RefreshNextRuns()
+
+	return nil
+}
+
+// DeleteCalendar 删除排除日历，并从引用它的任务中移除
+func (a *App) DeleteCalendar(id string) error {
+	for _, task := range a.storage.GetAllTasks() {
+		calendars := removeID(task.Calendars, id)
+		if len(calendars) == len(task.Calendars) {
+			continue
+		}
+		task.Calendars = calendars
+		if err := a.storage.SaveTask(task); err != nil {
+			log.Printf("Failed to update calendar references of task %s: %v", task.Name, err)
+		}
+	}
+
+	if err := a.storage.DeleteCalendar(id); err != nil {
+		return err
+	}
+
+	a.scheduler.RefreshNextRuns()
+
+	return nil
+}
+
+// ImportCalendarICS 选择 ICS 文件并将其中的事件导入到指定的排除日历
+func (a *App) ImportCalendarICS(id string) (*calendar.ImportResult, error) {
+	cal, err := a.storage.GetCalendar(id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择日历文件",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "iCalendar 文件 (*.ics)",
+				Pattern:     "*.ics",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	// 在副本上导入，解析失败时不修改原日历
+	updated := *cal
+	updated.Dates = append([]string(nil), cal.Dates...)
+	updated.Ranges = append([]models.ExclusionRange(nil), cal.Ranges...)
+	result, err := calendar.ImportICS(&updated, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import calendar: %w", err)
+	}
+
+	updated.UpdatedAt = time.Now()
+	if err := a.storage.SaveCalendar(&updated); err != nil {
+		return nil, err
+	}
+	a.scheduler.RefreshNextRuns()
+
+	log.Printf("Imported %d event(s) into calendar %s: %d date(s), %d range(s)", result.Events, cal.Name, result.Dates, result.Ranges)
+	return result, nil
+}
+
 // GetStats 获取统计信息
 func (a *App) GetStats() map[string]interface{} {
 	tasks := a.storage.GetAllTasks()
@@ -255,26 +638,106 @@ func (a *App) GetStats() map[string]interface{} {
 	}
 
 	successLogs := 0
+	skippedLogs := 0
 	for _, log := range logs {
 		if log.Success {
 			successLogs++
+		} else if log.Status == models.LogStatusSkipped {
+			skippedLogs++
 		}
 	}
 
+	paused, pausedUntil := a.scheduler.IsPaused()
+
 	return map[string]interface{}{
 		"totalTasks":       len(tasks),
 		"activeTasks":      activeTasks,
 		"totalLogs":        len(logs),
 		"successLogs":      successLogs,
-		"failedLogs":       len(logs) - successLogs,
+		"skippedLogs":      skippedLogs,
+		"failedLogs":       len(logs) - successLogs - skippedLogs,
 		"schedulerRunning": a.scheduler.IsRunning(),
+		"schedulerPaused":  paused,
+		"pausedUntil":      pausedUntil,
+		"runningTasks":     len(a.scheduler.RunningRuns()),
+		"queuedTasks":      len(a.scheduler.QueuedRuns()),
 	}
 }
 
-// ValidateCron 验证 cron 表达式
-func (a *App) ValidateCron(cronExpr string) bool {
-	// 简单验证
-	return len(cronExpr) > 0
+// GetSettings 获取全局设置
+func (a *App) GetSettings() *models.Settings {
+	return a.storage.GetSettings()
+}
+
+// UpdateSettings 更新全局设置
+func (a *App) UpdateSettings(settings *models.Settings) error {
+	if settings.MaxConcurrentTasks < 0 {
+		return fmt.Errorf("maxConcurrentTasks must not be negative")
+	}
+	if settings.LogsRetentionDays < 1 {
+		return fmt.Errorf("logsRetentionDays must be at least 1")
+	}
+	if settings.DefaultTimeout < 0 {
+		return fmt.Errorf("defaultTimeout must not be negative")
+	}
+	if settings.EnableWebhooks {
+		if _, _, err := net.SplitHostPort(settings.WebhookAddr); err != nil {
+			return fmt.Errorf("invalid webhook address %q: %w", settings.WebhookAddr, err)
+		}
+	}
+
+	// 按新设置启停 Webhook 服务，监听失败时不保存
+	old := a.storage.GetSettings()
+	switch {
+	case !settings.EnableWebhooks:
+		a.webhook.Stop()
+	case !old.EnableWebhooks || old.WebhookAddr != settings.WebhookAddr || a.webhook.Addr() == "":
+		if err := a.webhook.Start(settings.WebhookAddr); err != nil {
+			return err
+		}
+	}
+
+	if err := a.storage.SaveSettings(settings); err != nil {
+		return err
+	}
+
+	a.scheduler.SetMaxConcurrent(settings.MaxConcurrentTasks)
+	return nil
+}
+
+// GetQueuedRuns 获取等待执行槽位的运行
+func (a *App) GetQueuedRuns() []scheduler.RunInfo {
+	return a.scheduler.QueuedRuns()
+}
+
+// GetRunningRuns 获取正在执行的运行
+func (a *App) GetRunningRuns() []scheduler.RunInfo {
+	return a.scheduler.RunningRuns()
+}
+
+// GetRunOutput 获取运行中执行的已输出内容，用于在订阅实时事件前补齐历史输出
+// 执行已结束时返回 Running 为 false 的空结果，前端应改为读取日志
+func (a *App) GetRunOutput(runID string) *executor.LiveOutput {
+	output, err := a.executor.Output(runID)
+	if err != nil {
+		return &executor.LiveOutput{RunID: runID, Lines: []executor.OutputLine{}}
+	}
+	return output
+}
+
+// CancelRun 取消排队中或运行中的执行
+func (a *App) CancelRun(runID string) error {
+	return a.scheduler.CancelRun(runID)
+}
+
+// GenerateCron 根据调度类型和时间配置生成 cron 表达式
+func (a *App) GenerateCron(scheduleType models.ScheduleType, timeConfig models.TimeConfig) (string, error) {
+	return scheduler.BuildCron(&models.Task{ScheduleType: scheduleType, TimeConfig: timeConfig})
+}
+
+// ValidateCron 验证 cron 表达式，并返回按指定时区计算的之后 count 次执行时间
+func (a *App) ValidateCron(cronExpr string, timezone string, count int) *scheduler.CronValidation {
+	return scheduler.ValidateCron(cronExpr, timezone, count)
 }
 
 // GetAllScripts 获取所有脚本
@@ -293,6 +756,11 @@ func (a *App) CreateScript(script *models.Script) error {
 	script.ID = uuid.New().String()
 	script.CreatedAt = now
 	script.UpdatedAt = now
+	script.LastRunAt = nil
+
+	if script.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
 
 	if err := a.storage.SaveScript(script); err != nil {
 		return err
@@ -310,6 +778,12 @@ func (a *App) UpdateScript(script *models.Script) error {
 
 	script.CreatedAt = oldScript.CreatedAt
 	script.UpdatedAt = time.Now()
+	script.LastRunAt = oldScript.LastRunAt
+
+	// 超时时间由表单提交（编辑时以原值填充）
+	if script.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
 
 	return a.storage.SaveScript(script)
 }
@@ -327,13 +801,26 @@ func (a *App) RunScript(id string, sendNotify bool) error {
 	}
 
 	go func() {
+		runID := uuid.New().String()
+		release, ok := a.scheduler.AcquireSlot(runID, script.Name)
+		defer release()
+
 		startTime := time.Now()
-		result := a.executor.Execute(script.ScriptType, script.ScriptPath, script.ScriptCode)
+		result := &executor.ExecuteResult{Error: "cancelled by user", Cancelled: true}
+		if ok {
+			ctx := context.Background()
+			if timeout := a.storage.GetSettings().TimeoutFor(nil, script); timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			result = a.executor.Execute(ctx, runID, script.ScriptType, script.ScriptPath, script.ScriptCode, executor.RunInput{})
+		}
 		duration := time.Since(startTime).Milliseconds()
 
 		// 保存日志
 		log := &models.TaskLog{
-			ID:        uuid.New().String(),
+			ID:        runID,
 			TaskID:    "",
 			TaskName:  script.Name + " (手动执行)",
 			StartTime: startTime,
@@ -342,6 +829,9 @@ func (a *App) RunScript(id string, sendNotify bool) error {
 			Output:    result.Output,
 			Error:     result.Error,
 			Success:   result.Success,
+			Status:    result.Status(),
+			ExitCode:  result.ExitCode,
+			Trigger:   models.TriggerManual,
 		}
 
 		if err := a.storage.SaveLog(log); err != nil {
@@ -350,7 +840,7 @@ func (a *App) RunScript(id string, sendNotify bool) error {
 
 		// 发送通知（根据用户选择）
 		if sendNotify && a.notifier != nil {
-			a.notifier.Notify(log)
+			a.notify(log)
 		}
 
 		// 更新脚本最后运行时间
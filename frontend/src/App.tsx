@@ -245,19 +245,27 @@ function App() {
               <div className="flex items-center space-x-2">
                 <div
                   className={`w-2 h-2 rounded-full transition-all duration-300 ${
-                    stats.schedulerRunning
-                      ? "bg-emerald-500 ring-2 ring-emerald-500/20 shadow-sm shadow-emerald-500/50"
-                      : "bg-gray-400 ring-2 ring-gray-400/20"
+                    stats.schedulerPaused
+                      ? "bg-amber-500 ring-2 ring-amber-500/20"
+                      : stats.schedulerRunning
+                        ? "bg-emerald-500 ring-2 ring-emerald-500/20 shadow-sm shadow-emerald-500/50"
+                        : "bg-gray-400 ring-2 ring-gray-400/20"
                   }`}
                 />
                 <span
                   className={
-                    stats.schedulerRunning
-                      ? "text-emerald-700 font-medium"
-                      : "text-gray-500"
+                    stats.schedulerPaused
+                      ? "text-amber-700 font-medium"
+                      : stats.schedulerRunning
+                        ? "text-emerald-700 font-medium"
+                        : "text-gray-500"
                   }
                 >
-                  {stats.schedulerRunning ? "运行中" : "已停止"}
+                  {stats.schedulerPaused
+                    ? "已暂停"
+                    : stats.schedulerRunning
+                      ? "运行中"
+                      : "已停止"}
                 </span>
               </div>
               <div className="flex items-center space-x-1 text-gray-600">
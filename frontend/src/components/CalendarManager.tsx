@@ -0,0 +1,321 @@
+import { useEffect, useState } from "react";
+import {
+  CreateCalendar,
+  DeleteCalendar,
+  GetAllCalendars,
+  ImportCalendarICS,
+  UpdateCalendar,
+} from "../../wailsjs/go/main/App";
+import { models } from "../../wailsjs/go/models";
+import { Calendar, ExclusionWindow } from "../types";
+
+const weekdayNames = ["周日", "周一", "周二", "周三", "周四", "周五", "周六"];
+
+interface CalendarForm {
+  id?: string;
+  name: string;
+  description: string;
+  dates: string; // 每行一个日期
+  windows: ExclusionWindow[];
+  ranges: Calendar["ranges"];
+}
+
+const emptyForm: CalendarForm = {
+  name: "",
+  description: "",
+  dates: "",
+  windows: [{ name: "周末", weekdays: [0, 6], start: "", end: "" }],
+  ranges: [],
+};
+
+// 排除日历管理：日期列表、每周重复的排除窗口和 ICS 导入
+export default function CalendarManager() {
+  const [calendars, setCalendars] = useState<Calendar[]>([]);
+  const [form, setForm] = useState<CalendarForm | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    loadCalendars();
+  }, []);
+
+  const loadCalendars = async () => {
+    try {
+      const data = await GetAllCalendars();
+      setCalendars(
+        ((data || []) as unknown as Calendar[]).sort((a, b) =>
+          a.name.localeCompare(b.name),
+        ),
+      );
+    } catch (error) {
+      console.error("Failed to load calendars:", error);
+    }
+  };
+
+  const handleEdit = (calendar: Calendar) => {
+    setForm({
+      id: calendar.id,
+      name: calendar.name,
+      description: calendar.description,
+      dates: (calendar.dates || []).join("\n"),
+      windows: calendar.windows || [],
+      ranges: calendar.ranges || [],
+    });
+  };
+
+  const handleSave = async () => {
+    if (!form) return;
+    if (!form.name.trim()) {
+      alert("请输入日历名称");
+      return;
+    }
+
+    setSaving(true);
+    try {
+      const calendar = models.Calendar.createFrom({
+        id: form.id,
+        name: form.name.trim(),
+        description: form.description,
+        dates: form.dates
+          .split(/[\s,]+/)
+          .map((d) => d.trim())
+          .filter(Boolean),
+        windows: form.windows,
+        ranges: form.ranges,
+      });
+      if (form.id) {
+        await UpdateCalendar(calendar);
+      } else {
+        await CreateCalendar(calendar);
+      }
+      setForm(null);
+      loadCalendars();
+    } catch (error) {
+      alert("保存失败: " + error);
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleDelete = async (id: string) => {
+    if (!confirm("确定要删除这个日历吗？引用它的任务将不再排除这些时间。")) {
+      return;
+    }
+    try {
+      await DeleteCalendar(id);
+      loadCalendars();
+    } catch (error) {
+      alert("删除失败: " + error);
+    }
+  };
+
+  const handleImport = async (id: string) => {
+    try {
+      const result = await ImportCalendarICS(id);
+      if (!result) return;
+      let message = `已导入 ${result.events} 个事件：新增 ${result.dates} 个日期，${result.ranges} 个时间段`;
+      if (result.unsupported > 0) {
+        message += `\n${result.unsupported} 个事件的重复规则不受支持，只导入了首次发生`;
+      }
+      alert(message);
+      loadCalendars();
+    } catch (error) {
+      alert("导入失败: " + error);
+    }
+  };
+
+  const updateWindow = (index: number, updates: Partial<ExclusionWindow>) => {
+    if (!form) return;
+    const windows = form.windows.map((w, i) =>
+      i === index ? { ...w, ...updates } : w,
+    );
+    setForm({ ...form, windows });
+  };
+
+  const toggleWindowDay = (index: number, day: number) => {
+    if (!form) return;
+    const days = form.windows[index].weekdays || [];
+    updateWindow(index, {
+      weekdays: days.includes(day)
+        ? days.filter((d) => d !== day)
+        : [...days, day].sort(),
+    });
+  };
+
+  return (
+    <div className="space-y-4">
+      {calendars.length === 0 && !form && (
+        <p className="text-sm text-gray-500">
+          还没有排除日历。任务挂载日历后，落在排除日期或时间窗口内的定时执行会被跳过。
+        </p>
+      )}
+
+      {calendars.map((calendar) => (
+        <div
+          key={calendar.id}
+          className="flex items-center justify-between p-3 bg-gray-50 rounded-lg"
+        >
+          <div>
+            <div className="text-sm font-medium text-gray-900">
+              {calendar.name}
+            </div>
+            <div className="text-xs text-gray-500 mt-0.5">
+              {(calendar.dates || []).length} 个日期 ·{" "}
+              {(calendar.windows || []).length} 个周期窗口 ·{" "}
+              {(calendar.ranges || []).length} 个时间段
+            </div>
+          </div>
+          <div className="flex items-center space-x-2">
+            <button
+              onClick={() => handleImport(calendar.id)}
+              className="btn-sm btn-secondary"
+            >
+              导入 ICS
+            </button>
+            <button
+              onClick={() => handleEdit(calendar)}
+              className="btn-sm btn-secondary"
+            >
+              编辑
+            </button>
+            <button
+              onClick={() => handleDelete(calendar.id)}
+              className="btn-sm btn-danger"
+            >
+              删除
+            </button>
+          </div>
+        </div>
+      ))}
+
+      {form ? (
+        <div className="p-4 border border-gray-200 rounded-lg space-y-4">
+          <div>
+            <label className="label label-required">名称</label>
+            <input
+              type="text"
+              value={form.name}
+              onChange={(e) => setForm({ ...form, name: e.target.value })}
+              className="input"
+              placeholder="例如：法定节假日"
+            />
+          </div>
+
+          <div>
+            <label className="label">排除日期</label>
+            <textarea
+              value={form.dates}
+              onChange={(e) => setForm({ ...form, dates: e.target.value })}
+              className="textarea font-mono text-xs"
+              rows={4}
+              placeholder={"每行一个日期，格式 YYYY-MM-DD\n2026-10-01\n2026-10-02"}
+            />
+          </div>
+
+          <div>
+            <label className="label">每周排除窗口</label>
+            <div className="space-y-3">
+              {form.windows.map((window, index) => (
+                <div key={index} className="p-3 bg-gray-50 rounded-lg space-y-2">
+                  <div className="flex items-center space-x-2">
+                    <input
+                      type="text"
+                      value={window.name}
+                      onChange={(e) =>
+                        updateWindow(index, { name: e.target.value })
+                      }
+                      className="input flex-1"
+                      placeholder="窗口名称"
+                    />
+                    <input
+                      type="time"
+                      value={window.start}
+                      onChange={(e) =>
+                        updateWindow(index, { start: e.target.value })
+                      }
+                      className="input w-32"
+                    />
+                    <span className="text-gray-400">-</span>
+                    <input
+                      type="time"
+                      value={window.end}
+                      onChange={(e) =>
+                        updateWindow(index, { end: e.target.value })
+                      }
+                      className="input w-32"
+                    />
+                    <button
+                      type="button"
+                      onClick={() =>
+                        setForm({
+                          ...form,
+                          windows: form.windows.filter((_, i) => i !== index),
+                        })
+                      }
+                      className="btn-sm btn-danger"
+                    >
+                      移除
+                    </button>
+                  </div>
+                  <div className="grid grid-cols-7 gap-2">
+                    {weekdayNames.map((name, day) => (
+                      <button
+                        key={day}
+                        type="button"
+                        onClick={() => toggleWindowDay(index, day)}
+                        className={`p-1.5 rounded-lg text-xs font-medium transition-all ${
+                          window.weekdays?.includes(day)
+                            ? "bg-blue-500 text-white shadow-sm"
+                            : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50"
+                        }`}
+                      >
+                        {name}
+                      </button>
+                    ))}
+                  </div>
+                </div>
+              ))}
+              <button
+                type="button"
+                onClick={() =>
+                  setForm({
+                    ...form,
+                    windows: [
+                      ...form.windows,
+                      { name: "", weekdays: [], start: "", end: "" },
+                    ],
+                  })
+                }
+                className="btn-sm btn-secondary"
+              >
+                添加窗口
+              </button>
+              <p className="text-xs text-gray-500">
+                不填时间表示全天；结束时间早于开始时间表示跨越午夜；不选星期表示每天
+              </p>
+            </div>
+          </div>
+
+          <div className="flex justify-end space-x-2">
+            <button onClick={() => setForm(null)} className="btn-secondary">
+              取消
+            </button>
+            <button
+              onClick={handleSave}
+              disabled={saving}
+              className="btn-primary disabled:opacity-50"
+            >
+              {saving ? "保存中..." : "保存日历"}
+            </button>
+          </div>
+        </div>
+      ) : (
+        <button
+          onClick={() => setForm({ ...emptyForm })}
+          className="btn-secondary"
+        >
+          新建日历
+        </button>
+      )}
+    </div>
+  );
+}
@@ -1,22 +1,132 @@
+import { useEffect, useRef, useState } from "react";
 import { TaskLog } from "../types";
+import { GetRunOutput } from "../../wailsjs/go/main/App";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import LogStatusBadge, { logStatusInfo, logStatusOf } from "./LogStatusBadge";
 
 interface LogDetailModalProps {
   log: TaskLog;
   onClose: () => void;
+  live?: boolean; // 实时跟踪运行中的执行（log.id 为运行ID）
 }
 
-export default function LogDetailModal({ log, onClose }: LogDetailModalProps) {
+interface OutputEvent {
+  runId: string;
+  stream: string;
+  line: string;
+  seq: number; // 行序号，不大于快照 seq 的行已包含在快照中
+  done: boolean;
+}
+
+const formatLine = (stream: string, line: string) =>
+  stream === "stderr" ? `[STDERR] ${line}` : line;
+
+export default function LogDetailModal({
+  log,
+  onClose,
+  live = false,
+}: LogDetailModalProps) {
+  const [liveOutput, setLiveOutput] = useState("");
+  const [running, setRunning] = useState(live);
+  const outputRef = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    if (!live) return;
+
+    // 先订阅再获取快照，快照返回前的事件暂存，之后按序号与快照合并
+    let snapshotSeq: number | null = null;
+    let done = false;
+    const pending: OutputEvent[] = [];
+    const off = EventsOn(`run:output:${log.id}`, (event: OutputEvent) => {
+      if (event.done) {
+        done = true;
+        setRunning(false);
+        return;
+      }
+      if (snapshotSeq === null) {
+        pending.push(event);
+        return;
+      }
+      if (event.seq > snapshotSeq) {
+        setLiveOutput(
+          (prev) => prev + formatLine(event.stream, event.line) + "\n",
+        );
+      }
+    });
+
+    GetRunOutput(log.id).then((snapshot) => {
+      const lines = (snapshot.lines || []).map((l) =>
+        formatLine(l.stream, l.line),
+      );
+      for (const event of pending) {
+        if (event.seq > snapshot.seq) {
+          lines.push(formatLine(event.stream, event.line));
+        }
+      }
+      setLiveOutput(lines.length > 0 ? lines.join("\n") + "\n" : "");
+      setRunning(snapshot.running && !done);
+      snapshotSeq = snapshot.seq;
+    });
+
+    return off;
+  }, [live, log.id]);
+
+  useEffect(() => {
+    if (live && outputRef.current) {
+      outputRef.current.scrollTop = outputRef.current.scrollHeight;
+    }
+  }, [live, liveOutput]);
+
+  if (live) {
+    return (
+      <div className="modal-overlay animate-fade-in">
+        <div className="modal-content animate-slide-in">
+          <div className="modal-header">
+            <div className="flex items-center space-x-3">
+              <h2 className="modal-title">{log.taskName}</h2>
+              {running ? (
+                <span className="badge-info">运行中</span>
+              ) : (
+                <span className="badge-gray">已结束</span>
+              )}
+            </div>
+          </div>
+          <div className="modal-body">
+            <div
+              ref={outputRef}
+              className="code-block max-h-96 overflow-y-auto select-text cursor-text"
+              style={{ userSelect: "text", WebkitUserSelect: "text" }}
+            >
+              <pre
+                className="code-text select-text"
+                style={{ userSelect: "text", WebkitUserSelect: "text" }}
+              >
+                {liveOutput || (running ? "等待输出..." : "无输出")}
+              </pre>
+            </div>
+            {!running && (
+              <p className="mt-3 text-xs text-gray-500">
+                执行已结束，完整结果请在执行日志中查看
+              </p>
+            )}
+          </div>
+          <div className="modal-footer">
+            <button onClick={onClose} className="btn-secondary">
+              关闭
+            </button>
+          </div>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="modal-overlay animate-fade-in">
       <div className="modal-content animate-slide-in">
         <div className="modal-header">
           <div className="flex items-center space-x-3">
             <h2 className="modal-title">{log.taskName}</h2>
-            {log.success ? (
-              <span className="badge-success">成功</span>
-            ) : (
-              <span className="badge-danger">失败</span>
-            )}
+            <LogStatusBadge log={log} />
           </div>
           <button
             onClick={onClose}
@@ -70,11 +180,72 @@ export default function LogDetailModal({ log, onClose }: LogDetailModalProps) {
                 执行状态
               </p>
               <p className="font-semibold text-gray-900 text-sm">
-                {log.success ? "✅ 成功" : "❌ 失败"}
+                {logStatusInfo[logStatusOf(log)]?.icon}{" "}
+                {logStatusInfo[logStatusOf(log)]?.label}
               </p>
             </div>
           </div>
 
+          {/* Workflow Steps */}
+          {log.steps && log.steps.length > 0 && (
+            <div>
+              <h3 className="text-sm font-semibold text-gray-900 mb-3">
+                工作流步骤
+              </h3>
+              <div className="space-y-2">
+                {log.steps.map((step) => (
+                  <details
+                    key={step.step}
+                    className="bg-gray-50 rounded-lg p-3"
+                  >
+                    <summary className="flex items-center justify-between cursor-pointer text-sm">
+                      <span className="flex items-center space-x-2">
+                        <span>{logStatusInfo[step.status]?.icon || "❔"}</span>
+                        <span className="font-mono text-gray-900">
+                          {step.step}
+                        </span>
+                        {step.name && (
+                          <span className="text-gray-500">{step.name}</span>
+                        )}
+                      </span>
+                      {step.status !== "skipped" && (
+                        <span className="text-xs text-gray-500 font-mono">
+                          {formatDuration(step.duration)}
+                        </span>
+                      )}
+                    </summary>
+                    <pre
+                      className="mt-2 text-xs text-gray-800 whitespace-pre-wrap font-mono select-text"
+                      style={{ userSelect: "text", WebkitUserSelect: "text" }}
+                    >
+                      {[step.output, step.error].filter(Boolean).join("\n") ||
+                        "无输出"}
+                    </pre>
+                  </details>
+                ))}
+              </div>
+            </div>
+          )}
+
+          {/* Params */}
+          {log.params && Object.keys(log.params).length > 0 && (
+            <div>
+              <h3 className="text-sm font-semibold text-gray-900 mb-3">
+                执行参数
+              </h3>
+              <div className="bg-gray-50 rounded-lg p-3 space-y-1">
+                {Object.entries(log.params).map(([name, value]) => (
+                  <div key={name} className="flex text-sm font-mono">
+                    <span className="text-gray-500 w-40 truncate">{name}</span>
+                    <span className="text-gray-900 break-all select-text">
+                      {value || <span className="text-gray-400">（空）</span>}
+                    </span>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+
           {/* Error */}
           {log.error && (
             <div>
@@ -0,0 +1,25 @@
+import { LogStatus, TaskLog } from "../types";
+
+// 执行状态的显示名称、图标和徽章样式
+export const logStatusInfo: Record<
+  LogStatus,
+  { label: string; icon: string; badge: string }
+> = {
+  success: { label: "成功", icon: "✅", badge: "badge-success" },
+  failed: { label: "失败", icon: "❌", badge: "badge-danger" },
+  timeout: { label: "超时", icon: "⏱️", badge: "badge-warning" },
+  cancelled: { label: "已取消", icon: "⏹️", badge: "badge-gray" },
+  skipped: { label: "已跳过", icon: "⏭️", badge: "badge-gray" },
+  missed: { label: "未按时执行", icon: "⏰", badge: "badge-warning" },
+  overdue: { label: "执行过久", icon: "🐢", badge: "badge-warning" },
+};
+
+// 旧版本记录的日志没有 status，按 success 推断
+export function logStatusOf(log: TaskLog): LogStatus {
+  return log.status || (log.success ? "success" : "failed");
+}
+
+export default function LogStatusBadge({ log }: { log: TaskLog }) {
+  const info = logStatusInfo[logStatusOf(log)] || logStatusInfo.failed;
+  return <span className={info.badge}>{info.label}</span>;
+}
@@ -0,0 +1,96 @@
+import { useEffect, useState } from "react";
+import {
+  GetStats,
+  PauseScheduler,
+  ResumeScheduler,
+} from "../../wailsjs/go/main/App";
+import { Stats } from "../types";
+
+// 暂停和恢复所有定时执行，可设置自动恢复时间
+export default function SchedulerControl() {
+  const [stats, setStats] = useState<Stats | null>(null);
+  const [until, setUntil] = useState("");
+  const [busy, setBusy] = useState(false);
+
+  useEffect(() => {
+    loadStats();
+  }, []);
+
+  const loadStats = async () => {
+    try {
+      setStats((await GetStats()) as Stats);
+    } catch (error) {
+      console.error("Failed to load stats:", error);
+    }
+  };
+
+  const handlePause = async () => {
+    setBusy(true);
+    try {
+      await PauseScheduler(until ? new Date(until).toISOString() : null);
+      setUntil("");
+      loadStats();
+    } catch (error) {
+      alert("暂停失败: " + error);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleResume = async () => {
+    setBusy(true);
+    try {
+      await ResumeScheduler();
+      loadStats();
+    } catch (error) {
+      alert("恢复失败: " + error);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  if (!stats) return null;
+
+  if (stats.schedulerPaused) {
+    return (
+      <div className="flex items-center justify-between p-3 bg-amber-50 rounded-lg">
+        <div className="text-sm text-amber-800">
+          定时执行已暂停
+          {stats.pausedUntil
+            ? `，将于 ${new Date(stats.pausedUntil).toLocaleString("zh-CN")} 自动恢复`
+            : "，需要手动恢复"}
+        </div>
+        <button
+          onClick={handleResume}
+          disabled={busy}
+          className="btn-sm btn-primary disabled:opacity-50"
+        >
+          立即恢复
+        </button>
+      </div>
+    );
+  }
+
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center space-x-2">
+        <input
+          type="datetime-local"
+          value={until}
+          onChange={(e) => setUntil(e.target.value)}
+          className="input max-w-xs"
+        />
+        <button
+          onClick={handlePause}
+          disabled={busy}
+          className="btn-secondary disabled:opacity-50"
+        >
+          暂停定时执行
+        </button>
+      </div>
+      <p className="text-xs text-gray-500">
+        留空表示暂停到手动恢复；暂停期间任务状态不变，仍可立即运行，错过的定时执行恢复后不补跑
+      </p>
+    </div>
+  );
+}
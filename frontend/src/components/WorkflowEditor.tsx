@@ -0,0 +1,202 @@
+import { Script, StepFailure, Workflow, WorkflowStep } from "../types";
+
+interface WorkflowEditorProps {
+  workflow: Workflow;
+  scripts: Script[];
+  onChange: (workflow: Workflow) => void;
+}
+
+// 工作流步骤编辑：每个步骤选择脚本和依赖的步骤，无依赖关系的步骤并行执行
+export default function WorkflowEditor({
+  workflow,
+  scripts,
+  onChange,
+}: WorkflowEditorProps) {
+  const steps = workflow.steps || [];
+
+  const updateStep = (index: number, updates: Partial<WorkflowStep>) => {
+    onChange({
+      ...workflow,
+      steps: steps.map((s, i) => (i === index ? { ...s, ...updates } : s)),
+    });
+  };
+
+  // 修改步骤标识时同步更新其他步骤的依赖
+  const renameStep = (index: number, id: string) => {
+    const oldId = steps[index].id;
+    onChange({
+      ...workflow,
+      steps: steps.map((s, i) =>
+        i === index
+          ? { ...s, id }
+          : {
+              ...s,
+              dependsOn: (s.dependsOn || []).map((d) =>
+                d === oldId ? id : d,
+              ),
+            },
+      ),
+    });
+  };
+
+  const removeStep = (index: number) => {
+    const id = steps[index].id;
+    onChange({
+      ...workflow,
+      steps: steps
+        .filter((_, i) => i !== index)
+        .map((s) => ({
+          ...s,
+          dependsOn: (s.dependsOn || []).filter((d) => d !== id),
+        })),
+    });
+  };
+
+  const addStep = () => {
+    let n = steps.length + 1;
+    while (steps.some((s) => s.id === `step${n}`)) n++;
+    const last = steps[steps.length - 1];
+    onChange({
+      ...workflow,
+      steps: [
+        ...steps,
+        {
+          id: `step${n}`,
+          name: "",
+          scriptId: "",
+          dependsOn: last ? [last.id] : [],
+          timeout: 0,
+        },
+      ],
+    });
+  };
+
+  const toggleDependency = (index: number, dep: string) => {
+    const deps = steps[index].dependsOn || [];
+    updateStep(index, {
+      dependsOn: deps.includes(dep)
+        ? deps.filter((d) => d !== dep)
+        : [...deps, dep],
+    });
+  };
+
+  return (
+    <div className="space-y-3">
+      {steps.map((step, index) => (
+        <div key={index} className="p-3 bg-gray-50 rounded-lg space-y-2">
+          <div className="flex items-center space-x-2">
+            <input
+              type="text"
+              value={step.id}
+              onChange={(e) => renameStep(index, e.target.value.trim())}
+              className="input w-28 font-mono text-xs"
+              placeholder="步骤标识"
+            />
+            <input
+              type="text"
+              value={step.name}
+              onChange={(e) => updateStep(index, { name: e.target.value })}
+              className="input w-32"
+              placeholder="名称（可选）"
+            />
+            <select
+              value={step.scriptId}
+              onChange={(e) => updateStep(index, { scriptId: e.target.value })}
+              className="select flex-1"
+            >
+              <option value="">选择脚本...</option>
+              {scripts.map((script) => (
+                <option key={script.id} value={script.id}>
+                  {script.name}
+                </option>
+              ))}
+            </select>
+            <input
+              type="number"
+              min="0"
+              value={step.timeout || ""}
+              onChange={(e) =>
+                updateStep(index, { timeout: parseInt(e.target.value) || 0 })
+              }
+              className="input w-24"
+              placeholder="超时(秒)"
+            />
+            <button
+              type="button"
+              onClick={() => removeStep(index)}
+              className="btn-sm btn-danger"
+            >
+              移除
+            </button>
+          </div>
+          {steps.length > 1 && (
+            <div className="flex flex-wrap items-center gap-2 text-xs text-gray-600">
+              <span>依赖：</span>
+              {steps
+                .filter((_, i) => i !== index)
+                .map((other) => (
+                  <label key={other.id} className="flex items-center">
+                    <input
+                      type="checkbox"
+                      checked={(step.dependsOn || []).includes(other.id)}
+                      onChange={() => toggleDependency(index, other.id)}
+                      className="mr-1"
+                    />
+                    <span className="font-mono">{other.id}</span>
+                  </label>
+                ))}
+            </div>
+          )}
+        </div>
+      ))}
+
+      <button type="button" onClick={addStep} className="btn-sm btn-secondary">
+        添加步骤
+      </button>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div>
+          <label className="label">步骤失败时</label>
+          <select
+            value={workflow.onFailure || "stop"}
+            onChange={(e) =>
+              onChange({
+                ...workflow,
+                onFailure: e.target.value as StepFailure,
+              })
+            }
+            className="select"
+          >
+            <option value="stop">停止，不再启动新步骤</option>
+            <option value="continue">继续执行不依赖失败步骤的分支</option>
+            <option value="cleanup">停止并执行清理脚本</option>
+          </select>
+        </div>
+        {workflow.onFailure === "cleanup" && (
+          <div>
+            <label className="label label-required">清理脚本</label>
+            <select
+              value={workflow.cleanupId || ""}
+              onChange={(e) =>
+                onChange({ ...workflow, cleanupId: e.target.value })
+              }
+              className="select"
+            >
+              <option value="">选择脚本...</option>
+              {scripts.map((script) => (
+                <option key={script.id} value={script.id}>
+                  {script.name}
+                </option>
+              ))}
+            </select>
+          </div>
+        )}
+      </div>
+      <p className="text-xs text-gray-400">
+        依赖的步骤全部成功后才执行，没有依赖关系的步骤并行执行；步骤通过
+        TEMPO_STEP_ID 获取自身标识，清理脚本通过 TEMPO_FAILED_STEP
+        获取失败的步骤
+      </p>
+    </div>
+  );
+}
@@ -1,11 +1,16 @@
 import { useEffect, useState } from "react";
 import { Stats } from "../types";
 import {
+  CancelRun,
   GetAllTasks,
   GetAllLogs,
   GetAllScripts,
+  GetQueuedRuns,
+  GetRunningRuns,
 } from "../../wailsjs/go/main/App";
-import { Task, TaskLog, Script } from "../types";
+import { Task, TaskLog, Script, RunInfo } from "../types";
+import LogDetailModal from "../components/LogDetailModal";
+import LogStatusBadge from "../components/LogStatusBadge";
 
 interface DashboardPageProps {
   stats: Stats | null;
@@ -19,6 +24,9 @@ export default function DashboardPage({
   const [recentLogs, setRecentLogs] = useState<TaskLog[]>([]);
   const [upcomingTasks, setUpcomingTasks] = useState<Task[]>([]);
   const [scripts, setScripts] = useState<Script[]>([]);
+  const [runningRuns, setRunningRuns] = useState<RunInfo[]>([]);
+  const [queuedRuns, setQueuedRuns] = useState<RunInfo[]>([]);
+  const [tailingRun, setTailingRun] = useState<RunInfo | null>(null);
 
   useEffect(() => {
     loadData();
@@ -31,12 +39,16 @@ export default function DashboardPage({
       const logs = await GetAllLogs(5);
       setRecentLogs(logs as TaskLog[]);
 
-      const [tasks, scriptsData] = await Promise.all([
+      const [tasks, scriptsData, running, queued] = await Promise.all([
         GetAllTasks(),
         GetAllScripts(),
+        GetRunningRuns(),
+        GetQueuedRuns(),
       ]);
 
       setScripts(scriptsData as Script[]);
+      setRunningRuns(running as RunInfo[]);
+      setQueuedRuns(queued as RunInfo[]);
 
       const activeTasks = (tasks as Task[])
         .filter((t) => t.status === "active" && t.nextRunAt)
@@ -53,6 +65,16 @@ export default function DashboardPage({
     }
   };
 
+  const handleCancelRun = async (runId: string) => {
+    if (!confirm("确定要终止这次执行吗？")) return;
+    try {
+      await CancelRun(runId);
+      await loadData();
+    } catch (error) {
+      alert("终止失败: " + error);
+    }
+  };
+
   const getScriptById = (scriptId: string) => {
     return scripts.find((s) => s.id === scriptId);
   };
@@ -123,6 +145,64 @@ export default function DashboardPage({
         </div>
       </div>
 
+      {/* Running & Queued Runs */}
+      {(runningRuns.length > 0 || queuedRuns.length > 0) && (
+        <div className="bg-white border border-gray-200/80 rounded-xl p-5 shadow-sm">
+          <h2 className="text-base font-semibold text-gray-900 mb-3">
+            执行中
+          </h2>
+          <div className="space-y-1.5">
+            {[...runningRuns, ...queuedRuns].map((run) => (
+              <div
+                key={run.runId}
+                className="flex items-center justify-between py-2 px-3 hover:bg-gray-50/80 rounded-lg transition-all duration-200"
+              >
+                <div className="flex items-center space-x-3 min-w-0">
+                  {run.startedAt ? (
+                    <span className="badge-success">运行中</span>
+                  ) : (
+                    <span className="badge-gray">排队中</span>
+                  )}
+                  <button
+                    onClick={() => run.startedAt && setTailingRun(run)}
+                    className="text-sm font-medium text-gray-900 truncate hover:underline"
+                  >
+                    {run.taskName}
+                  </button>
+                  <span className="text-xs text-gray-400">
+                    {new Date(run.startedAt || run.queuedAt).toLocaleTimeString()}
+                  </span>
+                </div>
+                <button
+                  onClick={() => handleCancelRun(run.runId)}
+                  className="text-xs text-red-600 hover:text-red-700 font-medium transition-colors"
+                >
+                  终止
+                </button>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+
+      {tailingRun && (
+        <LogDetailModal
+          live
+          log={{
+            id: tailingRun.runId,
+            taskId: tailingRun.taskId,
+            taskName: tailingRun.taskName,
+            startTime: tailingRun.startedAt || tailingRun.queuedAt,
+            endTime: "",
+            duration: 0,
+            output: "",
+            error: "",
+            success: false,
+          }}
+          onClose={() => setTailingRun(null)}
+        />
+      )}
+
       <div className="grid grid-cols-1 lg:grid-cols-2 gap-5">
         {/* Upcoming Tasks */}
         <div className="bg-white border border-gray-200/80 rounded-xl p-5 shadow-sm">
@@ -336,15 +416,7 @@ export default function DashboardPage({
                         <p className="text-sm font-medium text-gray-900 truncate group-hover:text-gray-700">
                           {log.taskName}
                         </p>
-                        {log.success ? (
-                          <span className="inline-flex items-center px-1.5 py-0.5 rounded text-xs font-medium bg-emerald-100 text-emerald-700 border border-emerald-200/50">
-                            成功
-                          </span>
-                        ) : (
-                          <span className="inline-flex items-center px-1.5 py-0.5 rounded text-xs font-medium bg-red-100 text-red-700 border border-red-200/50">
-                            失败
-                          </span>
-                        )}
+                        <LogStatusBadge log={log} />
                       </div>
                       <div className="flex items-center space-x-2 text-xs text-gray-500">
                         <span>
@@ -1,12 +1,27 @@
 import { useEffect, useState } from "react";
 import { GetAllLogs, GetTaskLogs } from "../../wailsjs/go/main/App";
-import { TaskLog } from "../types";
+import { LogStatus, TaskLog } from "../types";
 import LogDetailModal from "../components/LogDetailModal";
+import LogStatusBadge, {
+  logStatusInfo,
+  logStatusOf,
+} from "../components/LogStatusBadge";
+
+// 筛选按钮，成功和失败之外的状态只在有日志时显示
+const statusFilters: { status: LogStatus; active: string }[] = [
+  { status: "success", active: "bg-emerald-500" },
+  { status: "failed", active: "bg-red-500" },
+  { status: "timeout", active: "bg-amber-500" },
+  { status: "cancelled", active: "bg-gray-500" },
+  { status: "skipped", active: "bg-gray-500" },
+  { status: "missed", active: "bg-amber-500" },
+  { status: "overdue", active: "bg-amber-500" },
+];
 
 export default function LogsPage() {
   const [logs, setLogs] = useState<TaskLog[]>([]);
   const [selectedLog, setSelectedLog] = useState<TaskLog | null>(null);
-  const [filter, setFilter] = useState<"all" | "success" | "failed">("all");
+  const [filter, setFilter] = useState<"all" | LogStatus>("all");
   const [loading, setLoading] = useState(false);
   const [autoRefresh, setAutoRefresh] = useState(true);
   const [refreshInterval, setRefreshInterval] = useState(5000); // 5秒刷新一次
@@ -35,11 +50,11 @@ export default function LogsPage() {
     }
   };
 
-  const filteredLogs = logs.filter((log) => {
-    if (filter === "success") return log.success;
-    if (filter === "failed") return !log.success;
-    return true;
-  });
+  const filteredLogs = logs.filter(
+    (log) => filter === "all" || logStatusOf(log) === filter,
+  );
+  const countOf = (status: LogStatus) =>
+    logs.filter((log) => logStatusOf(log) === status).length;
 
   return (
     <div className="space-y-5">
@@ -182,36 +197,34 @@ export default function LogsPage() {
             ({logs.length})
           </span>
         </button>
-        <button
-          onClick={() => setFilter("success")}
-          className={`px-4 py-2 rounded-lg font-medium text-sm transition-all duration-200 ${
-            filter === "success"
-              ? "bg-emerald-500 text-white shadow-sm"
-              : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50 hover:border-gray-300"
-          }`}
-        >
-          成功{" "}
-          <span
-            className={filter === "success" ? "text-white/80" : "text-gray-500"}
-          >
-            ({logs.filter((l) => l.success).length})
-          </span>
-        </button>
-        <button
-          onClick={() => setFilter("failed")}
-          className={`px-4 py-2 rounded-lg font-medium text-sm transition-all duration-200 ${
-            filter === "failed"
-              ? "bg-red-500 text-white shadow-sm"
-              : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50 hover:border-gray-300"
-          }`}
-        >
-          失败{" "}
-          <span
-            className={filter === "failed" ? "text-white/80" : "text-gray-500"}
-          >
-            ({logs.filter((l) => !l.success).length})
-          </span>
-        </button>
+        {statusFilters
+          .filter(
+            ({ status }) =>
+              status === "success" ||
+              status === "failed" ||
+              status === filter ||
+              countOf(status) > 0,
+          )
+          .map(({ status, active }) => (
+            <button
+              key={status}
+              onClick={() => setFilter(status)}
+              className={`px-4 py-2 rounded-lg font-medium text-sm transition-all duration-200 ${
+                filter === status
+                  ? `${active} text-white shadow-sm`
+                  : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50 hover:border-gray-300"
+              }`}
+            >
+              {logStatusInfo[status].label}{" "}
+              <span
+                className={
+                  filter === status ? "text-white/80" : "text-gray-500"
+                }
+              >
+                ({countOf(status)})
+              </span>
+            </button>
+          ))}
       </div>
 
       {/* Logs List */}
@@ -268,6 +281,7 @@ interface LogItemProps {
 }
 
 function LogItem({ log, onClick }: LogItemProps) {
+  const status = logStatusInfo[logStatusOf(log)] || logStatusInfo.failed;
   return (
     <button
       onClick={onClick}
@@ -279,11 +293,7 @@ function LogItem({ log, onClick }: LogItemProps) {
             <h3 className="font-semibold text-gray-900 truncate group-hover:text-gray-700">
               {log.taskName}
             </h3>
-            {log.success ? (
-              <span className="badge-success">成功</span>
-            ) : (
-              <span className="badge-danger">失败</span>
-            )}
+            <LogStatusBadge log={log} />
           </div>
 
           <div className="grid grid-cols-2 md:grid-cols-4 gap-4 text-sm">
@@ -308,7 +318,7 @@ function LogItem({ log, onClick }: LogItemProps) {
             <div>
               <p className="text-xs text-gray-500 mb-1 font-medium">状态</p>
               <p className="text-sm text-gray-900 font-medium">
-                {log.success ? "✅ 成功" : "❌ 失败"}
+                {status.icon} {status.label}
               </p>
             </div>
           </div>
@@ -376,7 +376,7 @@ function NotifierModal({ config, onClose, onSave }: NotifierModalProps) {
               <label className="label label-required">通知类型</label>
               <div className="grid grid-cols-2 gap-3">
                 {(
-                  ["dingtalk", "wechat", "lark", "webhook"] as NotifierType[]
+                  ["dingtalk", "wechat", "lark", "webhook", "email"] as NotifierType[]
                 ).map((type) => {
                   const typeInfo = {
                     dingtalk: { icon: "💬", name: "钉钉" },
@@ -519,6 +519,97 @@ function NotifierModal({ config, onClose, onSave }: NotifierModalProps) {
               </div>
             )}
 
+            {/* Email Config */}
+            {formData.type === "email" && (
+              <div className="space-y-4">
+                <div className="grid grid-cols-3 gap-3">
+                  <div className="col-span-2">
+                    <label className="label label-required">SMTP 服务器</label>
+                    <input
+                      type="text"
+                      required
+                      value={formData.config.host || ""}
+                      onChange={(e) => updateConfig("host", e.target.value)}
+                      className="input font-mono text-xs"
+                      placeholder="smtp.example.com"
+                    />
+                  </div>
+                  <div>
+                    <label className="label">端口</label>
+                    <input
+                      type="number"
+                      value={formData.config.port || ""}
+                      onChange={(e) => updateConfig("port", e.target.value)}
+                      className="input font-mono text-xs"
+                      placeholder="465"
+                    />
+                  </div>
+                </div>
+
+                <div>
+                  <label className="label">加密方式</label>
+                  <select
+                    value={formData.config.security || ""}
+                    onChange={(e) => updateConfig("security", e.target.value)}
+                    className="input"
+                  >
+                    <option value="">根据端口自动选择</option>
+                    <option value="tls">SSL/TLS（465）</option>
+                    <option value="starttls">STARTTLS（587）</option>
+                    <option value="none">不加密</option>
+                  </select>
+                </div>
+
+                <div className="grid grid-cols-2 gap-3">
+                  <div>
+                    <label className="label">用户名</label>
+                    <input
+                      type="text"
+                      value={formData.config.username || ""}
+                      onChange={(e) => updateConfig("username", e.target.value)}
+                      className="input font-mono text-xs"
+                      placeholder="user@example.com"
+                    />
+                  </div>
+                  <div>
+                    <label className="label">密码 / 授权码</label>
+                    <input
+                      type="password"
+                      value={formData.config.password || ""}
+                      onChange={(e) => updateConfig("password", e.target.value)}
+                      className="input font-mono text-xs"
+                    />
+                  </div>
+                </div>
+
+                <div>
+                  <label className="label">发件人</label>
+                  <input
+                    type="text"
+                    value={formData.config.from || ""}
+                    onChange={(e) => updateConfig("from", e.target.value)}
+                    className="input font-mono text-xs"
+                    placeholder="Tempo <user@example.com>（默认使用用户名）"
+                  />
+                </div>
+
+                <div>
+                  <label className="label label-required">收件人</label>
+                  <input
+                    type="text"
+                    required
+                    value={formData.config.to || ""}
+                    onChange={(e) => updateConfig("to", e.target.value)}
+                    className="input font-mono text-xs"
+                    placeholder="a@example.com, b@example.com"
+                  />
+                  <p className="mt-2 text-xs text-gray-400">
+                    多个收件人用逗号分隔，邮件同时包含纯文本和 HTML 正文
+                  </p>
+                </div>
+              </div>
+            )}
+
             <div className="flex items-center">
               <input
                 type="checkbox"
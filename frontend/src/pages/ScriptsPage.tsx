@@ -526,6 +526,7 @@ function ScriptModal({ script, onClose, onSave }: ScriptModalProps) {
     scriptPath: script?.scriptPath || "",
     scriptCode: script?.scriptCode || "",
     tags: script?.tags || [],
+    timeout: script?.timeout || 0,
   });
 
   const [useCode, setUseCode] = useState(
@@ -674,6 +675,25 @@ function ScriptModal({ script, onClose, onSave }: ScriptModalProps) {
               />
             </div>
 
+            <div>
+              <label className="label">超时时间（秒）</label>
+              <input
+                type="number"
+                min={0}
+                value={formData.timeout}
+                onChange={(e) =>
+                  setFormData({
+                    ...formData,
+                    timeout: parseInt(e.target.value) || 0,
+                  })
+                }
+                className="input max-w-xs"
+              />
+              <p className="text-xs text-gray-400 mt-2">
+                超过该时间仍未结束的执行会被终止；0 表示使用全局设置，任务设置的超时时间优先
+              </p>
+            </div>
+
             <div>
               <label className="label">标签</label>
               <div className="flex space-x-2 mb-2">
@@ -1,11 +1,22 @@
 import { useEffect, useState } from "react";
-import { GetScriptsDir, OpenDirectory } from "../../wailsjs/go/main/App";
+import {
+  GetScriptsDir,
+  GetSettings,
+  OpenDirectory,
+  UpdateSettings,
+} from "../../wailsjs/go/main/App";
+import { models } from "../../wailsjs/go/models";
+import CalendarManager from "../components/CalendarManager";
+import SchedulerControl from "../components/SchedulerControl";
 
 interface Settings {
   scriptsDir: string;
   logsRetentionDays: number;
   maxConcurrentTasks: number;
   enableNotifications: boolean;
+  defaultTimeout: number;
+  enableWebhooks: boolean;
+  webhookAddr: string;
 }
 
 export default function SettingsPage() {
@@ -14,6 +25,9 @@ export default function SettingsPage() {
     logsRetentionDays: 30,
     maxConcurrentTasks: 5,
     enableNotifications: true,
+    defaultTimeout: 300,
+    enableWebhooks: false,
+    webhookAddr: "127.0.0.1:17380",
   });
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
@@ -27,8 +41,9 @@ export default function SettingsPage() {
     try {
       setLoading(true);
       const scriptsDir = (await GetScriptsDir()) as string;
+      const saved = await GetSettings();
       setCurrentScriptsDir(scriptsDir);
-      setSettings((prev) => ({ ...prev, scriptsDir }));
+      setSettings((prev) => ({ ...prev, ...saved, scriptsDir }));
     } catch (error) {
       console.error("Failed to load settings:", error);
     } finally {
@@ -39,8 +54,16 @@ export default function SettingsPage() {
   const handleSave = async () => {
     setSaving(true);
     try {
-      // 这里可以调用后端 API 保存设置
-      // await UpdateSettings(settings);
+      await UpdateSettings(
+        models.Settings.createFrom({
+          maxConcurrentTasks: settings.maxConcurrentTasks,
+          logsRetentionDays: settings.logsRetentionDays,
+          enableNotifications: settings.enableNotifications,
+          defaultTimeout: settings.defaultTimeout,
+          enableWebhooks: settings.enableWebhooks,
+          webhookAddr: settings.webhookAddr,
+        }),
+      );
       alert("设置保存成功！");
     } catch (error) {
       alert("保存失败: " + error);
@@ -166,24 +189,133 @@ export default function SettingsPage() {
               <label className="label">最大并发任务数</label>
               <input
                 type="number"
-                min="1"
+                min="0"
                 max="20"
                 value={settings.maxConcurrentTasks}
                 onChange={(e) =>
                   setSettings({
                     ...settings,
-                    maxConcurrentTasks: parseInt(e.target.value),
+                    maxConcurrentTasks: parseInt(e.target.value) || 0,
+                  })
+                }
+                className="input max-w-xs"
+              />
+              <p className="mt-2 text-xs text-gray-500">
+                同时运行的最大任务数量，超出的执行排队等待，0 表示不限制，建议不超过 10
+              </p>
+            </div>
+
+            <div>
+              <label className="label">默认超时时间（秒）</label>
+              <input
+                type="number"
+                min="0"
+                value={settings.defaultTimeout}
+                onChange={(e) =>
+                  setSettings({
+                    ...settings,
+                    defaultTimeout: parseInt(e.target.value) || 0,
                   })
                 }
                 className="input max-w-xs"
               />
               <p className="mt-2 text-xs text-gray-500">
-                同时运行的最大任务数量，建议不超过 10
+                任务和脚本未单独设置超时时使用，0 表示不限制
               </p>
             </div>
           </div>
         </SettingSection>
 
+        {/* Webhook 触发 */}
+        <SettingSection
+          title="Webhook 触发"
+          description="允许 CI 等外部工具通过 HTTP 请求触发任务，任务需单独开启 Webhook"
+          icon={
+            <path
+              strokeLinecap="round"
+              strokeLinejoin="round"
+              strokeWidth={2}
+              d="M13.828 10.172a4 4 0 00-5.656 0l-4 4a4 4 0 105.656 5.656l1.102-1.101m-.758-4.899a4 4 0 005.656 0l4-4a4 4 0 00-5.656-5.656l-1.1 1.1"
+            />
+          }
+        >
+          <div className="space-y-4">
+            <div className="flex items-center justify-between">
+              <div>
+                <div className="text-sm font-medium text-gray-900">
+                  启用 Webhook 服务
+                </div>
+                <div className="text-xs text-gray-500 mt-1">
+                  请求体以文件形式传给脚本（TEMPO_INPUT_FILE），查询参数以
+                  TEMPO_QUERY_名称 环境变量传入
+                </div>
+              </div>
+              <label className="relative inline-flex items-center cursor-pointer">
+                <input
+                  type="checkbox"
+                  checked={settings.enableWebhooks}
+                  onChange={(e) =>
+                    setSettings({
+                      ...settings,
+                      enableWebhooks: e.target.checked,
+                    })
+                  }
+                  className="sr-only peer"
+                />
+                <div className="w-11 h-6 bg-gray-200 peer-focus:outline-none peer-focus:ring-4 peer-focus:ring-blue-300 rounded-full peer peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:border-gray-300 after:border after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-gray-900"></div>
+              </label>
+            </div>
+
+            <div>
+              <label className="label">监听地址</label>
+              <input
+                type="text"
+                value={settings.webhookAddr}
+                onChange={(e) =>
+                  setSettings({ ...settings, webhookAddr: e.target.value })
+                }
+                className="input max-w-xs font-mono text-xs"
+                placeholder="127.0.0.1:17380"
+              />
+              <p className="mt-2 text-xs text-gray-500">
+                默认只接受本机请求；需要局域网访问时改为 0.0.0.0:端口
+              </p>
+            </div>
+          </div>
+        </SettingSection>
+
+        {/* 暂停调度 */}
+        <SettingSection
+          title="暂停调度"
+          description="临时停止所有定时执行，例如维护期间，暂停状态在重启后保留"
+          icon={
+            <path
+              strokeLinecap="round"
+              strokeLinejoin="round"
+              strokeWidth={2}
+              d="M10 9v6m4-6v6m7-3a9 9 0 11-18 0 9 9 0 0118 0z"
+            />
+          }
+        >
+          <SchedulerControl />
+        </SettingSection>
+
+        {/* 排除日历 */}
+        <SettingSection
+          title="排除日历"
+          description="节假日、周末等不执行定时任务的日期和时间窗口，在任务中挂载后生效"
+          icon={
+            <path
+              strokeLinecap="round"
+              strokeLinejoin="round"
+              strokeWidth={2}
+              d="M8 7V3m8 4V3m-9 8h10M5 21h14a2 2 0 002-2V7a2 2 0 00-2-2H5a2 2 0 00-2 2v12a2 2 0 002 2z"
+            />
+          }
+        >
+          <CalendarManager />
+        </SettingSection>
+
         {/* 日志管理设置 */}
         <SettingSection
           title="日志管理"
@@ -6,9 +6,34 @@ import {
   DeleteTask,
   ToggleTaskStatus,
   RunTaskNow,
+  RunTaskWithParams,
   GetAllScripts,
+  GenerateCron,
+  GetAllCalendars,
+  ValidateCron,
+  GetWebhookURL,
+  RegenerateWebhookToken,
 } from "../../wailsjs/go/main/App";
-import { Task, Script, ScheduleType, TimeConfig } from "../types";
+import { scheduler } from "../../wailsjs/go/models";
+import WorkflowEditor from "../components/WorkflowEditor";
+import {
+  Task,
+  Script,
+  ScheduleType,
+  TimeConfig,
+  Calendar,
+  TriggerType,
+  WatchEvent,
+  Condition,
+  ConditionType,
+  TaskParam,
+  ParamMode,
+  Workflow,
+  NotifyPolicy,
+  BackoffType,
+  OverlapPolicy,
+  MisfirePolicy,
+} from "../types";
 
 interface TasksPageProps {
   onStatsUpdate: () => void;
@@ -19,6 +44,7 @@ export default function TasksPage({ onStatsUpdate }: TasksPageProps) {
   const [scripts, setScripts] = useState<Script[]>([]);
   const [showModal, setShowModal] = useState(false);
   const [editingTask, setEditingTask] = useState<Task | null>(null);
+  const [runningTask, setRunningTask] = useState<Task | null>(null);
   const [loading, setLoading] = useState(false);
 
   useEffect(() => {
@@ -74,6 +100,12 @@ export default function TasksPage({ onStatsUpdate }: TasksPageProps) {
   };
 
   const handleRunNow = async (id: string) => {
+    // 有参数的任务先确认参数
+    const task = tasks.find((t) => t.id === id);
+    if (task && (task.params || []).length > 0) {
+      setRunningTask(task);
+      return;
+    }
     try {
       await RunTaskNow(id);
       alert("任务已开始执行");
@@ -179,11 +211,89 @@ export default function TasksPage({ onStatsUpdate }: TasksPageProps) {
       {showModal && (
         <TaskModal
           task={editingTask}
+          tasks={tasks}
           scripts={scripts}
           onClose={() => setShowModal(false)}
           onSave={handleSaveTask}
         />
       )}
+
+      {runningTask && (
+        <RunParamsModal
+          task={runningTask}
+          onClose={() => setRunningTask(null)}
+        />
+      )}
+    </div>
+  );
+}
+
+interface RunParamsModalProps {
+  task: Task;
+  onClose: () => void;
+}
+
+// 带参数运行：以默认值填充，修改后的值只对本次执行生效
+function RunParamsModal({ task, onClose }: RunParamsModalProps) {
+  const params = task.params || [];
+  const [values, setValues] = useState<Record<string, string>>(() =>
+    Object.fromEntries(params.map((p) => [p.name, p.default])),
+  );
+  const [running, setRunning] = useState(false);
+
+  const handleRun = async () => {
+    setRunning(true);
+    try {
+      await RunTaskWithParams(task.id, values);
+      onClose();
+      alert("任务已开始执行");
+    } catch (error) {
+      alert("执行失败: " + error);
+    } finally {
+      setRunning(false);
+    }
+  };
+
+  return (
+    <div className="modal-overlay animate-fade-in">
+      <div className="modal-content animate-slide-in max-w-lg">
+        <div className="modal-header">
+          <h2 className="modal-title">运行 {task.name}</h2>
+        </div>
+        <div className="modal-body space-y-4">
+          {params.map((param) => (
+            <div key={param.name}>
+              <label className="label font-mono">{param.name}</label>
+              <input
+                type="text"
+                value={values[param.name] ?? ""}
+                onChange={(e) =>
+                  setValues({ ...values, [param.name]: e.target.value })
+                }
+                className="input"
+                placeholder={param.default}
+              />
+              {param.description && (
+                <p className="text-xs text-gray-400 mt-1">
+                  {param.description}
+                </p>
+              )}
+            </div>
+          ))}
+        </div>
+        <div className="modal-footer">
+          <button onClick={onClose} className="btn-secondary">
+            取消
+          </button>
+          <button
+            onClick={handleRun}
+            disabled={running}
+            className="btn-primary disabled:opacity-50"
+          >
+            {running ? "启动中..." : "运行"}
+          </button>
+        </div>
+      </div>
     </div>
   );
 }
@@ -216,9 +326,29 @@ function TaskCard({
     weekly: "每周",
     monthly: "每月",
     custom: "自定义",
+    interval: "间隔",
+    lastDayOfMonth: "月末",
+    nthWeekday: "每月第N周",
+    once: "一次性",
+  };
+
+  const triggerTypeLabels = {
+    schedule: "定时",
+    fileWatch: "文件监视",
+    startup: "启动时",
+    resume: "唤醒时",
   };
 
   const formatSchedule = () => {
+    if (task.triggerType === "fileWatch") {
+      return `文件变化: ${(task.fileWatch?.paths || []).join(", ")}`;
+    }
+    if (task.triggerType === "startup" || task.triggerType === "resume") {
+      const event = task.triggerType === "startup" ? "应用启动" : "系统唤醒";
+      return task.triggerDelay
+        ? `${event}后 ${task.triggerDelay} 秒`
+        : `${event}时`;
+    }
     const { scheduleType, timeConfig } = task;
     const hour = String(timeConfig.hour).padStart(2, "0");
     const minute = String(timeConfig.minute).padStart(2, "0");
@@ -240,6 +370,22 @@ function TaskCard({
         return `每周 ${time}`;
       case "monthly":
         return `每月${timeConfig.monthday}日 ${time}`;
+      case "interval":
+        return `每隔 ${timeConfig.interval} 分钟`;
+      case "lastDayOfMonth":
+        return `每月最后一天 ${time}`;
+      case "nthWeekday": {
+        const day = ["周日", "周一", "周二", "周三", "周四", "周五", "周六"][
+          timeConfig.weekday ?? 0
+        ];
+        const nth =
+          timeConfig.nth === -1 ? "最后一个" : `第${timeConfig.nth}个`;
+        return `每月${nth}${day} ${time}`;
+      }
+      case "once":
+        return task.completedAt
+          ? `已完成（${new Date(task.completedAt).toLocaleString("zh-CN")}）`
+          : `执行一次: ${task.runAt ? new Date(task.runAt).toLocaleString("zh-CN") : "-"}`;
       case "custom":
         return `自定义: ${task.cron}`;
       default:
@@ -254,7 +400,11 @@ function TaskCard({
           <div className="flex items-center space-x-3 mb-3">
             <div className="w-10 h-10 bg-gradient-to-br from-gray-100 to-gray-50 rounded-lg flex items-center justify-center flex-shrink-0 group-hover:from-gray-200 group-hover:to-gray-100 transition-all">
               <span className="text-xl">
-                {script ? scriptTypeIcons[script.scriptType] : "📄"}
+                {task.workflow?.steps?.length
+                  ? "🔀"
+                  : script
+                    ? scriptTypeIcons[script.scriptType]
+                    : "📄"}
               </span>
             </div>
             <div className="flex-1 min-w-0">
@@ -268,7 +418,9 @@ function TaskCard({
                   <span className="badge-gray">已停止</span>
                 )}
                 <span className="inline-flex items-center px-2 py-0.5 rounded-md text-xs font-medium bg-blue-100 text-blue-700 border border-blue-200/50">
-                  {scheduleTypeLabels[task.scheduleType]}
+                  {task.triggerType && task.triggerType !== "schedule"
+                    ? triggerTypeLabels[task.triggerType]
+                    : scheduleTypeLabels[task.scheduleType]}
                 </span>
               </div>
             </div>
@@ -286,7 +438,11 @@ function TaskCard({
                 执行脚本
               </p>
               <p className="text-sm text-gray-900 font-semibold truncate">
-                {script ? script.name : "未知脚本"}
+                {task.workflow?.steps?.length
+                  ? `工作流 · ${task.workflow.steps.length} 个步骤`
+                  : script
+                    ? script.name
+                    : "未知脚本"}
               </p>
             </div>
             <div className="p-3 bg-gray-50 rounded-lg">
@@ -296,6 +452,16 @@ function TaskCard({
               <p className="text-sm text-gray-900 font-semibold">
                 {formatSchedule()}
               </p>
+              {(task.maxRuns || 0) > 0 && (
+                <p className="text-xs text-gray-500 mt-1">
+                  已执行 {task.runCount || 0}/{task.maxRuns} 次
+                </p>
+              )}
+              {task.endAt && (
+                <p className="text-xs text-gray-500 mt-1">
+                  截止 {new Date(task.endAt).toLocaleString("zh-CN")}
+                </p>
+              )}
             </div>
             {task.lastRunAt && (
               <div className="p-3 bg-gray-50 rounded-lg">
@@ -308,6 +474,7 @@ function TaskCard({
                     day: "2-digit",
                     hour: "2-digit",
                     minute: "2-digit",
+                    timeZone: task.timezone || undefined,
                   })}
                 </p>
               </div>
@@ -323,6 +490,7 @@ function TaskCard({
                     day: "2-digit",
                     hour: "2-digit",
                     minute: "2-digit",
+                    timeZone: task.timezone || undefined,
                   })}
                 </p>
               </div>
@@ -424,18 +592,40 @@ function TaskCard({
   );
 }
 
+// toLocalInput 将 ISO 时间转换为 datetime-local 输入框使用的本地时间格式
+function toLocalInput(value: string): string {
+  const date = new Date(value);
+  const pad = (n: number) => String(n).padStart(2, "0");
+  return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())}T${pad(date.getHours())}:${pad(date.getMinutes())}`;
+}
+
 interface TaskModalProps {
   task: Task | null;
+  tasks: Task[];
   scripts: Script[];
   onClose: () => void;
   onSave: () => void;
 }
 
-function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
+function TaskModal({
+  task,
+  tasks,
+  scripts,
+  onClose,
+  onSave,
+}: TaskModalProps) {
   const [formData, setFormData] = useState({
     name: task?.name || "",
     description: task?.description || "",
     scriptId: task?.scriptId || "",
+    isWorkflow: (task?.workflow?.steps?.length || 0) > 0,
+    workflow: (task?.workflow || {
+      steps: [],
+      onFailure: "stop",
+      cleanupId: "",
+    }) as Workflow,
+    triggerType: task?.triggerType || ("schedule" as TriggerType),
+    triggerDelay: task?.triggerDelay || 0,
     scheduleType: task?.scheduleType || ("daily" as ScheduleType),
     timeConfig: task?.timeConfig || {
       hour: 0,
@@ -443,37 +633,163 @@ function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
       weekday: 1,
       monthday: 1,
       weekdays: [1, 2, 3, 4, 5],
+      interval: 30,
+      nth: 1,
     },
     cron: task?.cron || "0 0 0 * * *",
+    timezone: task?.timezone || "",
+    runAt: task?.runAt ? toLocalInput(task.runAt) : "",
+    calendars: task?.calendars || [],
+    jitter: task?.jitter || 0,
+    startAt: task?.startAt ? toLocalInput(task.startAt) : "",
+    endAt: task?.endAt ? toLocalInput(task.endAt) : "",
+    maxRuns: task?.maxRuns || 0,
+    notifyOn: task?.notifyOn || ("failure" as NotifyPolicy),
+    overlapPolicy: task?.overlapPolicy || ("allow" as OverlapPolicy),
+    timeout: task?.timeout || 0,
+    misfirePolicy: task?.misfirePolicy || ("ignore" as MisfirePolicy),
+    misfireLimit: task?.misfireLimit || 0,
+    retry: {
+      maxAttempts: task?.retry?.maxAttempts || 1,
+      backoff: task?.retry?.backoff || ("fixed" as BackoffType),
+      delay: task?.retry?.delay || 60,
+      maxDelay: task?.retry?.maxDelay || 0,
+    },
+    retryOnExitCodes: (task?.retry?.retryOnExitCodes || []).join(", "),
+    onSuccess: task?.onSuccess || ([] as string[]),
+    onFailure: task?.onFailure || ([] as string[]),
+    onComplete: task?.onComplete || ([] as string[]),
+    watchdog: task?.watchdog || { gracePeriod: 0, expectedDuration: 0 },
+    webhook: { enabled: task?.webhook?.enabled || false },
+    conditions: task?.conditions || ([] as Condition[]),
+    params: task?.params || ([] as TaskParam[]),
+    paramMode: task?.paramMode || ("env" as ParamMode),
+    fileWatch: {
+      paths: (task?.fileWatch?.paths || []).join("\n"),
+      patterns: (task?.fileWatch?.patterns || []).join(", "),
+      recursive: task?.fileWatch?.recursive || false,
+      events: task?.fileWatch?.events || (["create", "write"] as WatchEvent[]),
+      debounce: task?.fileWatch?.debounce || 1000,
+    },
   });
+  const [calendars, setCalendars] = useState<Calendar[]>([]);
 
-  const [saving, setSaving] = useState(false);
+  useEffect(() => {
+    GetAllCalendars()
+      .then((data) => setCalendars((data || []) as unknown as Calendar[]))
+      .catch(() => setCalendars([]));
+  }, []);
 
-  const generateCron = (): string => {
-    const { scheduleType, timeConfig } = formData;
-    const { hour, minute, weekday, monthday, weekdays } = timeConfig;
+  const toggleDownstream = (
+    field: "onSuccess" | "onFailure" | "onComplete",
+    id: string,
+  ) => {
+    const ids = formData[field];
+    setFormData({
+      ...formData,
+      [field]: ids.includes(id) ? ids.filter((t) => t !== id) : [...ids, id],
+    });
+  };
 
-    switch (scheduleType) {
-      case "daily":
-        return `0 ${minute} ${hour} * * *`;
-      case "weekly":
-        if (weekdays && weekdays.length > 0) {
-          return `0 ${minute} ${hour} * * ${weekdays.join(",")}`;
-        }
-        return `0 ${minute} ${hour} * * ${weekday}`;
-      case "monthly":
-        return `0 ${minute} ${hour} ${monthday} * *`;
-      case "custom":
-        return formData.cron;
-      default:
-        return "0 0 0 * * *";
+  const downstreamCandidates = tasks.filter((t) => t.id !== task?.id);
+
+  const toggleCalendar = (id: string) => {
+    setFormData({
+      ...formData,
+      calendars: formData.calendars.includes(id)
+        ? formData.calendars.filter((c) => c !== id)
+        : [...formData.calendars, id],
+    });
+  };
+
+  const updateCondition = (index: number, updates: Partial<Condition>) => {
+    setFormData({
+      ...formData,
+      conditions: formData.conditions.map((c, i) =>
+        i === index ? { ...c, ...updates } : c,
+      ),
+    });
+  };
+
+  const updateParam = (index: number, updates: Partial<TaskParam>) => {
+    setFormData({
+      ...formData,
+      params: formData.params.map((p, i) =>
+        i === index ? { ...p, ...updates } : p,
+      ),
+    });
+  };
+
+  const [webhookURL, setWebhookURL] = useState("");
+
+  useEffect(() => {
+    if (!task?.webhook?.enabled) return;
+    GetWebhookURL(task.id)
+      .then(setWebhookURL)
+      .catch(() => setWebhookURL(""));
+  }, [task]);
+
+  const handleRegenerateToken = async () => {
+    if (!task || !confirm("重新生成后旧的触发地址将立即失效，确定继续吗？")) {
+      return;
+    }
+    try {
+      setWebhookURL(await RegenerateWebhookToken(task.id));
+    } catch (error) {
+      alert("生成失败: " + error);
     }
   };
 
+  const [saving, setSaving] = useState(false);
+  const [cronCheck, setCronCheck] = useState<scheduler.CronValidation | null>(
+    null,
+  );
+
+  useEffect(() => {
+    if (formData.scheduleType !== "custom") {
+      setCronCheck(null);
+      return;
+    }
+    const timer = setTimeout(() => {
+      ValidateCron(formData.cron, formData.timezone, 5)
+        .then(setCronCheck)
+        .catch(() => setCronCheck(null));
+    }, 300);
+    return () => clearTimeout(timer);
+  }, [formData.scheduleType, formData.cron, formData.timezone]);
+
+  const [generatedCron, setGeneratedCron] = useState("");
+  const [generateError, setGenerateError] = useState("");
+
+  // 非自定义类型的 cron 表达式由后端根据时间配置生成
+  useEffect(() => {
+    if (formData.scheduleType === "custom" || formData.scheduleType === "once") {
+      return;
+    }
+    GenerateCron(formData.scheduleType, formData.timeConfig as any)
+      .then((expr) => {
+        setGeneratedCron(expr);
+        setGenerateError("");
+      })
+      .catch((error) => {
+        setGeneratedCron("");
+        setGenerateError(String(error));
+      });
+  }, [formData.scheduleType, formData.timeConfig]);
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
 
-    if (!formData.scriptId) {
+    if (formData.isWorkflow) {
+      if (formData.workflow.steps.length === 0) {
+        alert("请为工作流添加至少一个步骤");
+        return;
+      }
+      if (formData.workflow.steps.some((s) => !s.scriptId)) {
+        alert("请为每个步骤选择脚本");
+        return;
+      }
+    } else if (!formData.scriptId) {
       alert("请选择要执行的脚本");
       return;
     }
@@ -481,9 +797,37 @@ function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
     setSaving(true);
 
     try {
+      const { isWorkflow, retryOnExitCodes, ...fields } = formData;
       const taskData: any = {
-        ...formData,
-        cron: generateCron(),
+        ...fields,
+        retry: {
+          ...formData.retry,
+          retryOnExitCodes: retryOnExitCodes
+            .split(/[\s,]+/)
+            .filter(Boolean)
+            .map((c) => parseInt(c))
+            .filter((c) => !isNaN(c)),
+        },
+        scriptId: isWorkflow ? "" : formData.scriptId,
+        workflow: isWorkflow ? formData.workflow : null,
+        runAt:
+          formData.scheduleType === "once" && formData.runAt
+            ? new Date(formData.runAt).toISOString()
+            : null,
+        startAt: formData.startAt
+          ? new Date(formData.startAt).toISOString()
+          : null,
+        endAt: formData.endAt ? new Date(formData.endAt).toISOString() : null,
+        fileWatch: {
+          ...formData.fileWatch,
+          paths: formData.fileWatch.paths
+            .split("\n")
+            .map((p) => p.trim())
+            .filter(Boolean),
+          patterns: formData.fileWatch.patterns
+            .split(/[\s,]+/)
+            .filter(Boolean),
+        },
       };
 
       if (task) {
@@ -580,11 +924,46 @@ function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
             </div>
 
             <div>
-              <label className="label label-required">选择脚本</label>
+              <label className="label label-required">执行内容</label>
+              <div className="grid grid-cols-2 gap-3 mb-3">
+                {[
+                  { value: false, label: "单个脚本", icon: "📄" },
+                  { value: true, label: "工作流", icon: "🔀" },
+                ].map((kind) => (
+                  <button
+                    key={kind.label}
+                    type="button"
+                    onClick={() =>
+                      setFormData({ ...formData, isWorkflow: kind.value })
+                    }
+                    className={`p-3 border-2 rounded-xl transition-all duration-200 ${
+                      formData.isWorkflow === kind.value
+                        ? "border-gray-900 bg-gray-50 shadow-sm"
+                        : "border-gray-200 hover:border-gray-300 hover:bg-gray-50"
+                    }`}
+                  >
+                    <span className="text-sm font-semibold text-gray-900">
+                      {kind.icon} {kind.label}
+                    </span>
+                  </button>
+                ))}
+              </div>
               {scripts.length === 0 ? (
                 <div className="p-4 bg-yellow-50 border border-yellow-200 rounded-lg text-sm text-yellow-700">
                   还没有可用的脚本，请先在脚本管理页面添加脚本
                 </div>
+              ) : formData.isWorkflow ? (
+                <WorkflowEditor
+                  workflow={formData.workflow}
+                  scripts={scripts}
+                  onChange={(workflow) =>
+                    setFormData({ ...formData, workflow })
+                  }
+                />
+              ) : (
+                <div className="p-4 bg-yellow-50 border border-yellow-200 rounded-lg text-sm text-yellow-700">
+                  还没有可用的脚本，请先在脚本管理页面添加脚本
+                </div>
               ) : (
                 <select
                   value={formData.scriptId}
@@ -608,13 +987,13 @@ function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
             </div>
 
             <div>
-              <label className="label label-required">执行频率</label>
-              <div className="grid grid-cols-4 gap-3 mb-4">
+              <label className="label label-required">触发方式</label>
+              <div className="grid grid-cols-4 gap-3">
                 {[
-                  { value: "daily", label: "每天", icon: "📅" },
-                  { value: "weekly", label: "每周", icon: "📆" },
-                  { value: "monthly", label: "每月", icon: "🗓️" },
-                  { value: "custom", label: "自定义", icon: "⚙️" },
+                  { value: "schedule", label: "定时执行", icon: "⏰" },
+                  { value: "fileWatch", label: "文件变化", icon: "📂" },
+                  { value: "startup", label: "应用启动", icon: "🚀" },
+                  { value: "resume", label: "系统唤醒", icon: "🌅" },
                 ].map((option) => (
                   <button
                     key={option.value}
@@ -622,137 +1001,1044 @@ function TaskModal({ task, scripts, onClose, onSave }: TaskModalProps) {
                     onClick={() =>
                       setFormData({
                         ...formData,
-                        scheduleType: option.value as ScheduleType,
+                        triggerType: option.value as TriggerType,
                       })
                     }
-                    className={`p-4 border-2 rounded-xl transition-all duration-200 ${
-                      formData.scheduleType === option.value
+                    className={`p-3 border-2 rounded-xl transition-all duration-200 ${
+                      formData.triggerType === option.value
                         ? "border-gray-900 bg-gray-50 shadow-sm"
                         : "border-gray-200 hover:border-gray-300 hover:bg-gray-50"
                     }`}
                   >
-                    <div className="text-center">
-                      <span className="text-2xl block mb-2">{option.icon}</span>
-                      <span className="text-sm font-semibold text-gray-900">
-                        {option.label}
-                      </span>
-                    </div>
+                    <span className="text-sm font-semibold text-gray-900">
+                      {option.icon} {option.label}
+                    </span>
                   </button>
                 ))}
               </div>
+            </div>
+
+            {(formData.triggerType === "startup" ||
+              formData.triggerType === "resume") && (
+              <div>
+                <label className="label">延迟执行（秒）</label>
+                <input
+                  type="number"
+                  min={0}
+                  value={formData.triggerDelay}
+                  onChange={(e) =>
+                    setFormData({
+                      ...formData,
+                      triggerDelay: parseInt(e.target.value) || 0,
+                    })
+                  }
+                  className="input max-w-xs"
+                />
+                <p className="text-xs text-gray-400 mt-2">
+                  {formData.triggerType === "startup"
+                    ? "每次 Tempo 启动后执行一次"
+                    : "每次系统从休眠唤醒后执行一次"}
+                  ，延迟用于等待网络等就绪；调度暂停期间不执行
+                </p>
+              </div>
+            )}
+
+            {formData.triggerType === "fileWatch" && (
+              <div className="space-y-4">
+                <div>
+                  <label className="label label-required">监视路径</label>
+                  <textarea
+                    required
+                    value={formData.fileWatch.paths}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        fileWatch: {
+                          ...formData.fileWatch,
+                          paths: e.target.value,
+                        },
+                      })
+                    }
+                    className="textarea font-mono text-xs"
+                    rows={2}
+                    placeholder={"每行一个文件或目录的绝对路径\n/Users/me/Downloads/inbox"}
+                  />
+                </div>
+                <div className="grid grid-cols-2 gap-4">
+                  <div>
+                    <label className="label">文件名匹配</label>
+                    <input
+                      type="text"
+                      value={formData.fileWatch.patterns}
+                      onChange={(e) =>
+                        setFormData({
+                          ...formData,
+                          fileWatch: {
+                            ...formData.fileWatch,
+                            patterns: e.target.value,
+                          },
+                        })
+                      }
+                      className="input font-mono text-xs"
+                      placeholder="*.csv, report-*.xlsx"
+                    />
+                  </div>
+                  <div>
+                    <label className="label">防抖时间（毫秒）</label>
+                    <input
+                      type="number"
+                      min={0}
+                      value={formData.fileWatch.debounce}
+                      onChange={(e) =>
+                        setFormData({
+                          ...formData,
+                          fileWatch: {
+                            ...formData.fileWatch,
+                            debounce: parseInt(e.target.value) || 0,
+                          },
+                        })
+                      }
+                      className="input"
+                    />
+                  </div>
+                </div>
+                <div className="flex flex-wrap items-center gap-4 text-sm text-gray-700">
+                  {(
+                    [
+                      { value: "create", label: "新建" },
+                      { value: "write", label: "修改" },
+                      { value: "remove", label: "删除" },
+                      { value: "rename", label: "重命名" },
+                    ] as { value: WatchEvent; label: string }[]
+                  ).map((option) => (
+                    <label key={option.value} className="flex items-center">
+                      <input
+                        type="checkbox"
+                        checked={formData.fileWatch.events.includes(
+                          option.value,
+                        )}
+                        onChange={(e) =>
+                          setFormData({
+                            ...formData,
+                            fileWatch: {
+                              ...formData.fileWatch,
+                              events: e.target.checked
+                                ? [...formData.fileWatch.events, option.value]
+                                : formData.fileWatch.events.filter(
+                                    (ev) => ev !== option.value,
+                                  ),
+                            },
+                          })
+                        }
+                        className="mr-1.5"
+                      />
+                      {option.label}
+                    </label>
+                  ))}
+                  <label className="flex items-center">
+                    <input
+                      type="checkbox"
+                      checked={formData.fileWatch.recursive}
+                      onChange={(e) =>
+                        setFormData({
+                          ...formData,
+                          fileWatch: {
+                            ...formData.fileWatch,
+                            recursive: e.target.checked,
+                          },
+                        })
+                      }
+                      className="mr-1.5"
+                    />
+                    包含子目录
+                  </label>
+                </div>
+                <p className="text-xs text-gray-400">
+                  每个变化的文件在防抖时间内只触发一次；脚本可通过环境变量
+                  TEMPO_EVENT_PATH、TEMPO_EVENT_NAME、TEMPO_EVENT_OP 获取变化的文件路径、文件名和事件类型
+                </p>
+              </div>
+            )}
+
+            {formData.triggerType === "schedule" && (
+              <div>
+                <label className="label label-required">执行频率</label>
+                <div className="grid grid-cols-4 gap-3 mb-4">
+                  {[
+                    { value: "daily", label: "每天", icon: "📅" },
+                    { value: "weekly", label: "每周", icon: "📆" },
+                    { value: "monthly", label: "每月", icon: "🗓️" },
+                    { value: "interval", label: "间隔", icon: "⏱️" },
+                    { value: "lastDayOfMonth", label: "月末", icon: "🔚" },
+                    { value: "nthWeekday", label: "第N个周X", icon: "🔢" },
+                    { value: "once", label: "一次性", icon: "1️⃣" },
+                    { value: "custom", label: "自定义", icon: "⚙️" },
+                  ].map((option) => (
+                    <button
+                      key={option.value}
+                      type="button"
+                      onClick={() =>
+                        setFormData({
+                          ...formData,
+                          scheduleType: option.value as ScheduleType,
+                        })
+                      }
+                      className={`p-4 border-2 rounded-xl transition-all duration-200 ${
+                        formData.scheduleType === option.value
+                          ? "border-gray-900 bg-gray-50 shadow-sm"
+                          : "border-gray-200 hover:border-gray-300 hover:bg-gray-50"
+                      }`}
+                    >
+                      <div className="text-center">
+                        <span className="text-2xl block mb-2">{option.icon}</span>
+                        <span className="text-sm font-semibold text-gray-900">
+                          {option.label}
+                        </span>
+                      </div>
+                    </button>
+                  ))}
+                </div>
 
-              {/* 时间配置 */}
-              {formData.scheduleType !== "custom" && (
-                <div className="p-4 bg-gray-50 rounded-lg space-y-4">
-                  <div className="grid grid-cols-2 gap-4">
+                {formData.scheduleType === "once" && (
+                  <div>
+                    <label className="label label-required">执行时间</label>
+                    <input
+                      type="datetime-local"
+                      required
+                      value={formData.runAt}
+                      onChange={(e) =>
+                        setFormData({ ...formData, runAt: e.target.value })
+                      }
+                      className="input"
+                    />
+                    <p className="text-xs text-gray-400 mt-2">
+                      到达执行时间后运行一次，随后任务自动停用
+                    </p>
+                  </div>
+                )}
+
+                {/* 时间配置 */}
+                {formData.scheduleType !== "custom" &&
+                  formData.scheduleType !== "once" && (
+                  <div className="p-4 bg-gray-50 rounded-lg space-y-4">
+                    {formData.scheduleType === "interval" ? (
+                      <div>
+                        <label className="label">间隔（分钟）</label>
+                        <input
+                          type="number"
+                          min={1}
+                          value={formData.timeConfig.interval ?? 30}
+                          onChange={(e) =>
+                            updateTimeConfig({
+                              interval: parseInt(e.target.value) || 0,
+                            })
+                          }
+                          className="input"
+                        />
+                      </div>
+                    ) : (
+                      <div className="grid grid-cols-2 gap-4">
+                        <div>
+                          <label className="label">小时</label>
+                          <select
+                            value={formData.timeConfig.hour}
+                            onChange={(e) =>
+                              updateTimeConfig({ hour: parseInt(e.target.value) })
+                            }
+                            className="select"
+                          >
+                            {Array.from({ length: 24 }, (_, i) => (
+                              <option key={i} value={i}>
+                                {String(i).padStart(2, "0")}:00
+                              </option>
+                            ))}
+                          </select>
+                        </div>
+                        <div>
+                          <label className="label">分钟</label>
+                          <select
+                            value={formData.timeConfig.minute}
+                            onChange={(e) =>
+                              updateTimeConfig({ minute: parseInt(e.target.value) })
+                            }
+                            className="select"
+                          >
+                            {[0, 15, 30, 45].map((m) => (
+                              <option key={m} value={m}>
+                                {String(m).padStart(2, "0")}
+                              </option>
+                            ))}
+                          </select>
+                        </div>
+                      </div>
+                    )}
+
+                    {formData.scheduleType === "weekly" && (
+                      <div>
+                        <label className="label">选择星期几</label>
+                        <div className="grid grid-cols-7 gap-2">
+                          {weekdayNames.map((name, index) => (
+                            <button
+                              key={index}
+                              type="button"
+                              onClick={() => toggleWeekday(index)}
+                              className={`p-2 rounded-lg text-sm font-medium transition-all ${
+                                formData.timeConfig.weekdays?.includes(index)
+                                  ? "bg-blue-500 text-white shadow-sm"
+                                  : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50"
+                              }`}
+                            >
+                              {name}
+                            </button>
+                          ))}
+                        </div>
+                      </div>
+                    )}
+
+                    {formData.scheduleType === "monthly" && (
+                      <div>
+                        <label className="label">每月第几天</label>
+                        <select
+                          value={formData.timeConfig.monthday || 1}
+                          onChange={(e) =>
+                            updateTimeConfig({
+                              monthday: parseInt(e.target.value),
+                            })
+                          }
+                          className="select"
+                        >
+                          {Array.from({ length: 31 }, (_, i) => i + 1).map(
+                            (d) => (
+                              <option key={d} value={d}>
+                                {d} 日
+                              </option>
+                            ),
+                          )}
+                        </select>
+                      </div>
+                    )}
+
+                    {formData.scheduleType === "nthWeekday" && (
+                      <div className="grid grid-cols-2 gap-4">
+                        <div>
+                          <label className="label">第几个</label>
+                          <select
+                            value={formData.timeConfig.nth ?? 1}
+                            onChange={(e) =>
+                              updateTimeConfig({ nth: parseInt(e.target.value) })
+                            }
+                            className="select"
+                          >
+                            {[1, 2, 3, 4, 5].map((n) => (
+                              <option key={n} value={n}>
+                                第 {n} 个
+                              </option>
+                            ))}
+                            <option value={-1}>最后一个</option>
+                          </select>
+                        </div>
+                        <div>
+                          <label className="label">星期几</label>
+                          <select
+                            value={formData.timeConfig.weekday ?? 1}
+                            onChange={(e) =>
+                              updateTimeConfig({
+                                weekday: parseInt(e.target.value),
+                              })
+                            }
+                            className="select"
+                          >
+                            {weekdayNames.map((name, index) => (
+                              <option key={index} value={index}>
+                                {name}
+                              </option>
+                            ))}
+                          </select>
+                        </div>
+                      </div>
+                    )}
+
+                    <div className="pt-3 border-t border-gray-200">
+                      <p className="text-xs text-gray-500 mb-1">
+                        生成的 Cron 表达式:
+                      </p>
+                      <code className="text-sm font-mono bg-gray-900 text-green-400 px-3 py-2 rounded block">
+                        {generatedCron || "-"}
+                      </code>
+                      {generateError && (
+                        <p className="text-xs text-red-600 mt-2">
+                          {generateError}
+                        </p>
+                      )}
+                    </div>
+                  </div>
+                )}
+
+                {formData.scheduleType === "custom" && (
+                  <div>
+                    <label className="label label-required">Cron 表达式</label>
+                    <input
+                      type="text"
+                      required
+                      value={formData.cron}
+                      onChange={(e) =>
+                        setFormData({ ...formData, cron: e.target.value })
+                      }
+                      className="input font-mono text-xs"
+                      placeholder="0 0 0 * * * (秒 分 时 日 月 周)"
+                    />
+                    <p className="text-xs text-gray-400 mt-2">
+                      格式: [秒] 分 时 日 月 周，也支持 @daily、@every 5m、L（月末）、1#2（第2个周一）等写法
+                    </p>
+                    {cronCheck && !cronCheck.valid && cronCheck.error && (
+                      <p className="text-xs text-red-600 mt-2 font-mono">
+                        {cronCheck.error.field
+                          ? `第 ${cronCheck.error.position + 1} 个字符（${cronCheck.error.field}）: `
+                          : ""}
+                        {cronCheck.error.message}
+                      </p>
+                    )}
+                    {cronCheck && cronCheck.valid && (
+                      <div className="mt-2 p-3 bg-gray-50 rounded-lg">
+                        <p className="text-xs text-gray-500 mb-1">接下来的执行时间:</p>
+                        <ul className="text-xs font-mono text-gray-700 space-y-0.5">
+                          {cronCheck.nextRuns.map((t: string) => (
+                            <li key={t}>
+                              {new Date(t).toLocaleString("zh-CN", {
+                                timeZone: formData.timezone || undefined,
+                              })}
+                            </li>
+                          ))}
+                        </ul>
+                      </div>
+                    )}
+                  </div>
+                )}
+              </div>
+            )}
+
+            {calendars.length > 0 && (
+              <div>
+                <label className="label">排除日历</label>
+                <div className="flex flex-wrap gap-2">
+                  {calendars.map((calendar) => (
+                    <button
+                      key={calendar.id}
+                      type="button"
+                      onClick={() => toggleCalendar(calendar.id)}
+                      className={`px-3 py-1.5 rounded-lg text-sm font-medium transition-all ${
+                        formData.calendars.includes(calendar.id)
+                          ? "bg-blue-500 text-white shadow-sm"
+                          : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50"
+                      }`}
+                    >
+                      {calendar.name}
+                    </button>
+                  ))}
+                </div>
+                <p className="text-xs text-gray-400 mt-2">
+                  定时执行落在所选日历的排除范围内时跳过，并记录到日志
+                </p>
+              </div>
+            )}
+
+            <div>
+              <label className="label">有效期</label>
+              <div className="grid grid-cols-3 gap-4">
+                <div>
+                  <p className="text-xs text-gray-500 mb-1">开始时间</p>
+                  <input
+                    type="datetime-local"
+                    value={formData.startAt}
+                    onChange={(e) =>
+                      setFormData({ ...formData, startAt: e.target.value })
+                    }
+                    className="input"
+                  />
+                </div>
+                <div>
+                  <p className="text-xs text-gray-500 mb-1">结束时间</p>
+                  <input
+                    type="datetime-local"
+                    value={formData.endAt}
+                    onChange={(e) =>
+                      setFormData({ ...formData, endAt: e.target.value })
+                    }
+                    className="input"
+                  />
+                </div>
+                <div>
+                  <p className="text-xs text-gray-500 mb-1">最多执行次数</p>
+                  <input
+                    type="number"
+                    min={0}
+                    value={formData.maxRuns}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        maxRuns: parseInt(e.target.value) || 0,
+                      })
+                    }
+                    className="input"
+                  />
+                </div>
+              </div>
+              <p className="text-xs text-gray-400 mt-2">
+                均为可选；到达结束时间或执行次数（0 表示不限制）后任务自动停用
+              </p>
+            </div>
+
+            <div>
+              <label className="label">随机延迟（秒）</label>
+              <input
+                type="number"
+                min={0}
+                value={formData.jitter}
+                onChange={(e) =>
+                  setFormData({
+                    ...formData,
+                    jitter: parseInt(e.target.value) || 0,
+                  })
+                }
+                className="input max-w-xs"
+              />
+              <p className="text-xs text-gray-400 mt-2">
+                每次定时执行在该时间窗口内随机延后开始，避免多个任务同时请求同一服务；0 表示不延迟
+              </p>
+            </div>
+
+            <div>
+              <label className="label">执行通知</label>
+              <select
+                value={formData.notifyOn}
+                onChange={(e) =>
+                  setFormData({
+                    ...formData,
+                    notifyOn: e.target.value as NotifyPolicy,
+                  })
+                }
+                className="select max-w-xs"
+              >
+                <option value="failure">仅失败时通知</option>
+                <option value="always">每次执行都通知</option>
+                <option value="success">仅成功时通知</option>
+                <option value="never">不通知</option>
+              </select>
+              <p className="text-xs text-gray-400 mt-2">
+                通过已启用的通知渠道发送执行结果，需在设置中开启通知
+              </p>
+            </div>
+
+            {formData.triggerType === "schedule" && (
+              <div>
+                <label className="label">错过执行时</label>
+                <div className="flex items-center space-x-3">
+                  <select
+                    value={formData.misfirePolicy}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        misfirePolicy: e.target.value as MisfirePolicy,
+                      })
+                    }
+                    className="select max-w-xs"
+                  >
+                    <option value="ignore">忽略</option>
+                    <option value="once">补跑一次</option>
+                    <option value="all">补跑全部</option>
+                  </select>
+                  {formData.misfirePolicy === "all" && (
+                    <input
+                      type="number"
+                      min={0}
+                      value={formData.misfireLimit || ""}
+                      onChange={(e) =>
+                        setFormData({
+                          ...formData,
+                          misfireLimit: parseInt(e.target.value) || 0,
+                        })
+                      }
+                      className="input w-40"
+                      placeholder="最多补跑 10 次"
+                    />
+                  )}
+                </div>
+                <p className="text-xs text-gray-400 mt-2">
+                  应用未运行或系统休眠期间错过的定时执行，在启动或唤醒后如何处理
+                </p>
+              </div>
+            )}
+
+            <div>
+              <label className="label">超时时间（秒）</label>
+              <input
+                type="number"
+                min={0}
+                value={formData.timeout}
+                onChange={(e) =>
+                  setFormData({
+                    ...formData,
+                    timeout: parseInt(e.target.value) || 0,
+                  })
+                }
+                className="input max-w-xs"
+              />
+              <p className="text-xs text-gray-400 mt-2">
+                超过该时间仍未结束的执行会被终止；0 表示使用脚本或全局设置。工作流任务限制整个工作流的时长
+              </p>
+            </div>
+
+            <div>
+              <label className="label">上次未结束时</label>
+              <select
+                value={formData.overlapPolicy}
+                onChange={(e) =>
+                  setFormData({
+                    ...formData,
+                    overlapPolicy: e.target.value as OverlapPolicy,
+                  })
+                }
+                className="select max-w-xs"
+              >
+                <option value="allow">同时运行</option>
+                <option value="skip">跳过本次</option>
+                <option value="queue">排队，结束后再运行一次</option>
+              </select>
+            </div>
+
+            <div>
+              <label className="label">失败重试</label>
+              <div className="grid grid-cols-4 gap-4">
+                <div>
+                  <span className="text-xs text-gray-500">最多尝试次数</span>
+                  <input
+                    type="number"
+                    min={1}
+                    value={formData.retry.maxAttempts}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        retry: {
+                          ...formData.retry,
+                          maxAttempts: parseInt(e.target.value) || 1,
+                        },
+                      })
+                    }
+                    className="input"
+                  />
+                </div>
+                {formData.retry.maxAttempts > 1 && (
+                  <>
                     <div>
-                      <label className="label">小时</label>
+                      <span className="text-xs text-gray-500">间隔方式</span>
                       <select
-                        value={formData.timeConfig.hour}
+                        value={formData.retry.backoff}
                         onChange={(e) =>
-                          updateTimeConfig({ hour: parseInt(e.target.value) })
+                          setFormData({
+                            ...formData,
+                            retry: {
+                              ...formData.retry,
+                              backoff: e.target.value as BackoffType,
+                            },
+                          })
                         }
                         className="select"
                       >
-                        {Array.from({ length: 24 }, (_, i) => (
-                          <option key={i} value={i}>
-                            {String(i).padStart(2, "0")}:00
-                          </option>
-                        ))}
+                        <option value="fixed">固定间隔</option>
+                        <option value="exponential">指数退避</option>
                       </select>
                     </div>
                     <div>
-                      <label className="label">分钟</label>
-                      <select
-                        value={formData.timeConfig.minute}
+                      <span className="text-xs text-gray-500">
+                        首次间隔（秒）
+                      </span>
+                      <input
+                        type="number"
+                        min={0}
+                        value={formData.retry.delay}
                         onChange={(e) =>
-                          updateTimeConfig({ minute: parseInt(e.target.value) })
+                          setFormData({
+                            ...formData,
+                            retry: {
+                              ...formData.retry,
+                              delay: parseInt(e.target.value) || 0,
+                            },
+                          })
                         }
-                        className="select"
-                      >
-                        {[0, 15, 30, 45].map((m) => (
-                          <option key={m} value={m}>
-                            {String(m).padStart(2, "0")}
-                          </option>
-                        ))}
-                      </select>
+                        className="input"
+                      />
                     </div>
-                  </div>
+                    {formData.retry.backoff === "exponential" && (
+                      <div>
+                        <span className="text-xs text-gray-500">
+                          最大间隔（秒）
+                        </span>
+                        <input
+                          type="number"
+                          min={0}
+                          value={formData.retry.maxDelay}
+                          onChange={(e) =>
+                            setFormData({
+                              ...formData,
+                              retry: {
+                                ...formData.retry,
+                                maxDelay: parseInt(e.target.value) || 0,
+                              },
+                            })
+                          }
+                          className="input"
+                        />
+                      </div>
+                    )}
+                  </>
+                )}
+              </div>
+              {formData.retry.maxAttempts > 1 && (
+                <input
+                  type="text"
+                  value={formData.retryOnExitCodes}
+                  onChange={(e) =>
+                    setFormData({
+                      ...formData,
+                      retryOnExitCodes: e.target.value,
+                    })
+                  }
+                  className="input mt-2 font-mono text-xs"
+                  placeholder="仅这些退出码才重试，如 1, 75；留空表示任意失败都重试"
+                />
+              )}
+              <p className="text-xs text-gray-400 mt-2">
+                失败或超时后按间隔重试，1 表示不重试；等待重试的执行显示在排队列表中，可以取消
+              </p>
+            </div>
 
-                  {formData.scheduleType === "weekly" && (
-                    <div>
-                      <label className="label">选择星期几</label>
-                      <div className="grid grid-cols-7 gap-2">
-                        {weekdayNames.map((name, index) => (
+            <div>
+              <label className="label">触发下游任务</label>
+              {downstreamCandidates.length === 0 ? (
+                <p className="text-sm text-gray-500">没有其他任务可以触发</p>
+              ) : (
+                <div className="space-y-3">
+                  {(
+                    [
+                      { field: "onSuccess", label: "成功后" },
+                      { field: "onFailure", label: "失败后" },
+                      { field: "onComplete", label: "结束后（无论成败）" },
+                    ] as const
+                  ).map(({ field, label }) => (
+                    <div key={field}>
+                      <p className="text-xs text-gray-500 mb-1">{label}</p>
+                      <div className="flex flex-wrap gap-2">
+                        {downstreamCandidates.map((t) => (
                           <button
-                            key={index}
+                            key={t.id}
                             type="button"
-                            onClick={() => toggleWeekday(index)}
-                            className={`p-2 rounded-lg text-sm font-medium transition-all ${
-                              formData.timeConfig.weekdays?.includes(index)
+                            onClick={() => toggleDownstream(field, t.id)}
+                            className={`px-2.5 py-1 rounded-lg text-xs font-medium transition-all ${
+                              formData[field].includes(t.id)
                                 ? "bg-blue-500 text-white shadow-sm"
                                 : "bg-white border border-gray-200 text-gray-700 hover:bg-gray-50"
                             }`}
                           >
-                            {name}
+                            {t.name}
                           </button>
                         ))}
                       </div>
                     </div>
-                  )}
+                  ))}
+                </div>
+              )}
+              <p className="text-xs text-gray-400 mt-2">
+                本任务执行结束后按结果触发选中的任务，不能形成循环
+              </p>
+            </div>
 
-                  {formData.scheduleType === "monthly" && (
-                    <div>
-                      <label className="label">每月第几天</label>
+            <div>
+              <label className="label">看门狗告警</label>
+              <div className="grid grid-cols-2 gap-4">
+                <div>
+                  <span className="text-xs text-gray-500">宽限期（秒）</span>
+                  <input
+                    type="number"
+                    min={0}
+                    value={formData.watchdog.gracePeriod}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        watchdog: {
+                          ...formData.watchdog,
+                          gracePeriod: parseInt(e.target.value) || 0,
+                        },
+                      })
+                    }
+                    className="input"
+                  />
+                </div>
+                <div>
+                  <span className="text-xs text-gray-500">预期时长（秒）</span>
+                  <input
+                    type="number"
+                    min={0}
+                    value={formData.watchdog.expectedDuration}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        watchdog: {
+                          ...formData.watchdog,
+                          expectedDuration: parseInt(e.target.value) || 0,
+                        },
+                      })
+                    }
+                    className="input"
+                  />
+                </div>
+              </div>
+              <p className="text-xs text-gray-400 mt-2">
+                计划时间过后宽限期内没有开始执行、或运行超过预期时长时发送告警，不受通知策略限制；0 表示不检查
+              </p>
+            </div>
+
+            <div>
+              <label className="label">前置条件</label>
+              <div className="space-y-2">
+                {formData.conditions.map((condition, index) => (
+                  <div key={index} className="flex items-center space-x-2">
+                    <select
+                      value={condition.type}
+                      onChange={(e) =>
+                        updateCondition(index, {
+                          type: e.target.value as ConditionType,
+                        })
+                      }
+                      className="select w-40"
+                    >
+                      <option value="script">检查脚本成功</option>
+                      <option value="url">URL 可访问</option>
+                      <option value="fileExists">文件存在</option>
+                      <option value="previousFailed">上次执行失败</option>
+                    </select>
+                    {condition.type === "script" && (
                       <select
-                        value={formData.timeConfig.monthday || 1}
+                        value={condition.scriptId || ""}
                         onChange={(e) =>
-                          updateTimeConfig({
-                            monthday: parseInt(e.target.value),
-                          })
+                          updateCondition(index, { scriptId: e.target.value })
                         }
-                        className="select"
+                        className="select flex-1"
                       >
-                        {Array.from({ length: 31 }, (_, i) => i + 1).map(
-                          (d) => (
-                            <option key={d} value={d}>
-                              {d} 日
-                            </option>
-                          ),
-                        )}
+                        <option value="">选择检查脚本...</option>
+                        {scripts.map((script) => (
+                          <option key={script.id} value={script.id}>
+                            {script.name}
+                          </option>
+                        ))}
                       </select>
-                    </div>
-                  )}
+                    )}
+                    {condition.type === "url" && (
+                      <input
+                        type="text"
+                        value={condition.url || ""}
+                        onChange={(e) =>
+                          updateCondition(index, { url: e.target.value })
+                        }
+                        className="input flex-1 font-mono text-xs"
+                        placeholder="https://example.com/health"
+                      />
+                    )}
+                    {condition.type === "fileExists" && (
+                      <input
+                        type="text"
+                        value={condition.path || ""}
+                        onChange={(e) =>
+                          updateCondition(index, { path: e.target.value })
+                        }
+                        className="input flex-1 font-mono text-xs"
+                        placeholder="/path/to/inbox/*.csv"
+                      />
+                    )}
+                    {condition.type === "previousFailed" && (
+                      <div className="flex-1" />
+                    )}
+                    <label className="flex items-center text-sm text-gray-700 whitespace-nowrap">
+                      <input
+                        type="checkbox"
+                        checked={condition.negate || false}
+                        onChange={(e) =>
+                          updateCondition(index, { negate: e.target.checked })
+                        }
+                        className="mr-1.5"
+                      />
+                      取反
+                    </label>
+                    <button
+                      type="button"
+                      onClick={() =>
+                        setFormData({
+                          ...formData,
+                          conditions: formData.conditions.filter(
+                            (_, i) => i !== index,
+                          ),
+                        })
+                      }
+                      className="btn-sm btn-danger"
+                    >
+                      移除
+                    </button>
+                  </div>
+                ))}
+                <button
+                  type="button"
+                  onClick={() =>
+                    setFormData({
+                      ...formData,
+                      conditions: [
+                        ...formData.conditions,
+                        { type: "fileExists", path: "" },
+                      ],
+                    })
+                  }
+                  className="btn-sm btn-secondary"
+                >
+                  添加条件
+                </button>
+              </div>
+              <p className="text-xs text-gray-400 mt-2">
+                每次执行前依次检查，全部成立才执行，否则记录为跳过；立即运行不检查
+              </p>
+            </div>
 
-                  <div className="pt-3 border-t border-gray-200">
-                    <p className="text-xs text-gray-500 mb-1">
-                      生成的 Cron 表达式:
-                    </p>
-                    <code className="text-sm font-mono bg-gray-900 text-green-400 px-3 py-2 rounded block">
-                      {generateCron()}
-                    </code>
+            <div>
+              <label className="label">参数</label>
+              <div className="space-y-2">
+                {formData.params.map((param, index) => (
+                  <div key={index} className="flex items-center space-x-2">
+                    <input
+                      type="text"
+                      value={param.name}
+                      onChange={(e) =>
+                        updateParam(index, { name: e.target.value })
+                      }
+                      className="input w-36 font-mono text-xs"
+                      placeholder="参数名"
+                    />
+                    <input
+                      type="text"
+                      value={param.default}
+                      onChange={(e) =>
+                        updateParam(index, { default: e.target.value })
+                      }
+                      className="input w-36 font-mono text-xs"
+                      placeholder="默认值"
+                    />
+                    <input
+                      type="text"
+                      value={param.description || ""}
+                      onChange={(e) =>
+                        updateParam(index, { description: e.target.value })
+                      }
+                      className="input flex-1"
+                      placeholder="说明"
+                    />
+                    <button
+                      type="button"
+                      onClick={() =>
+                        setFormData({
+                          ...formData,
+                          params: formData.params.filter((_, i) => i !== index),
+                        })
+                      }
+                      className="btn-sm btn-danger"
+                    >
+                      移除
+                    </button>
                   </div>
+                ))}
+                <div className="flex items-center space-x-2">
+                  <button
+                    type="button"
+                    onClick={() =>
+                      setFormData({
+                        ...formData,
+                        params: [...formData.params, { name: "", default: "" }],
+                      })
+                    }
+                    className="btn-sm btn-secondary"
+                  >
+                    添加参数
+                  </button>
+                  {formData.params.length > 0 && (
+                    <select
+                      value={formData.paramMode}
+                      onChange={(e) =>
+                        setFormData({
+                          ...formData,
+                          paramMode: e.target.value as ParamMode,
+                        })
+                      }
+                      className="select w-56"
+                    >
+                      <option value="env">环境变量 TEMPO_PARAM_名称</option>
+                      <option value="args">命令行参数 --名称=值</option>
+                      <option value="both">环境变量和命令行参数</option>
+                    </select>
+                  )}
                 </div>
-              )}
+              </div>
+              <p className="text-xs text-gray-400 mt-2">
+                定时和其他触发使用默认值；手动运行时可以修改本次使用的值
+              </p>
+            </div>
 
-              {formData.scheduleType === "custom" && (
-                <div>
-                  <label className="label label-required">Cron 表达式</label>
+            <div>
+              <label className="flex items-center text-sm font-medium text-gray-900">
+                <input
+                  type="checkbox"
+                  checked={formData.webhook.enabled}
+                  onChange={(e) =>
+                    setFormData({
+                      ...formData,
+                      webhook: { enabled: e.target.checked },
+                    })
+                  }
+                  className="mr-2"
+                />
+                允许通过 Webhook 触发
+              </label>
+              {formData.webhook.enabled && webhookURL && (
+                <div className="mt-2 flex items-center space-x-2">
                   <input
                     type="text"
-                    required
-                    value={formData.cron}
-                    onChange={(e) =>
-                      setFormData({ ...formData, cron: e.target.value })
-                    }
-                    className="input font-mono text-xs"
-                    placeholder="0 0 0 * * * (秒 分 时 日 月 周)"
+                    readOnly
+                    value={webhookURL}
+                    className="input flex-1 font-mono text-xs bg-gray-50"
+                    onFocus={(e) => e.target.select()}
                   />
-                  <p className="text-xs text-gray-400 mt-2">
-                    格式: 秒 分 时 日 月 周（支持 6 位表达式）
-                  </p>
+                  <button
+                    type="button"
+                    onClick={handleRegenerateToken}
+                    className="btn-sm btn-secondary whitespace-nowrap"
+                  >
+                    重新生成
+                  </button>
                 </div>
               )}
+              <p className="text-xs text-gray-400 mt-2">
+                {formData.webhook.enabled && !webhookURL
+                  ? "保存后生成触发地址；"
+                  : ""}
+                向触发地址发送 POST 请求即可运行任务，需在设置中启用 Webhook 服务
+              </p>
+            </div>
+
+            <div>
+              <label className="label">时区</label>
+              <input
+                type="text"
+                value={formData.timezone}
+                onChange={(e) =>
+                  setFormData({ ...formData, timezone: e.target.value })
+                }
+                className="input font-mono text-xs"
+                placeholder="留空使用本机时区，例如 Asia/Shanghai"
+              />
             </div>
           </form>
         </div>
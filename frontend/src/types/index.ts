@@ -4,7 +4,28 @@ export type ScriptType = "python" | "nodejs" | "shell";
 
 export type NotifierType = "email" | "dingtalk" | "wechat" | "lark" | "webhook";
 
-export type ScheduleType = "daily" | "weekly" | "monthly" | "custom";
+export type ScheduleType =
+  | "daily"
+  | "weekly"
+  | "monthly"
+  | "custom"
+  | "interval"
+  | "lastDayOfMonth"
+  | "nthWeekday"
+  | "once";
+
+export type NotifyPolicy = "always" | "failure" | "success" | "never";
+
+export type OverlapPolicy = "allow" | "skip" | "queue";
+
+export type LogStatus =
+  | "success"
+  | "failed"
+  | "skipped"
+  | "cancelled"
+  | "timeout"
+  | "missed"
+  | "overdue";
 
 export interface Script {
   id: string;
@@ -14,6 +35,7 @@ export interface Script {
   scriptPath: string;
   scriptCode: string;
   tags: string[];
+  timeout?: number; // 超时时间（秒），0 表示使用全局默认值
   createdAt: string;
   updatedAt: string;
   lastRunAt?: string;
@@ -25,16 +47,136 @@ export interface TimeConfig {
   weekday?: number; // 0-6, 0=周日
   monthday?: number; // 1-31
   weekdays?: number[]; // 多个星期几
+  interval?: number; // 间隔分钟数
+  nth?: number; // 第几个（1-5，-1 表示最后一个）
+}
+
+export type TriggerSource =
+  | "schedule"
+  | "manual"
+  | "chain"
+  | "catchup"
+  | "fileWatch"
+  | "webhook"
+  | "startup"
+  | "resume";
+
+export type TriggerType = "schedule" | "fileWatch" | "startup" | "resume";
+
+export type WatchEvent = "create" | "write" | "remove" | "rename";
+
+export interface FileWatch {
+  paths: string[]; // 监视的文件或目录（绝对路径）
+  patterns?: string[]; // 文件名通配符，如 *.csv
+  recursive: boolean; // 是否监视子目录
+  events?: WatchEvent[]; // 为空表示 create 和 write
+  debounce: number; // 防抖时间（毫秒），0 表示默认值
+}
+
+export type MisfirePolicy = "ignore" | "once" | "all";
+
+export type BackoffType = "fixed" | "exponential";
+
+export interface WatchdogRule {
+  gracePeriod: number; // 计划时间过后多久未执行视为错过（秒），0 表示不检查
+  expectedDuration: number; // 预期运行时长（秒），超过后告警，0 表示不检查
+}
+
+export type ConditionType = "script" | "url" | "fileExists" | "previousFailed";
+
+export interface Condition {
+  type: ConditionType;
+  scriptId?: string; // script：检查脚本ID，退出码为 0 时成立
+  url?: string; // url：状态码小于 400 时成立
+  path?: string; // fileExists：文件路径，支持通配符
+  negate?: boolean; // 条件取反
+}
+
+export type StepFailure = "stop" | "continue" | "cleanup";
+
+export interface WorkflowStep {
+  id: string; // 步骤标识，工作流内唯一
+  name: string;
+  scriptId: string;
+  dependsOn?: string[]; // 依赖的步骤，全部成功后才执行
+  timeout?: number; // 超时时间（秒），0 表示使用脚本或全局设置
+}
+
+export interface Workflow {
+  steps: WorkflowStep[];
+  onFailure?: StepFailure; // 步骤失败时的处理方式，为空表示停止
+  cleanupId?: string; // 清理脚本ID
+}
+
+export interface StepLog {
+  step: string; // 步骤标识，清理脚本为 cleanup
+  name: string;
+  runId: string;
+  startTime: string;
+  endTime: string;
+  duration: number;
+  output: string;
+  error: string;
+  status: LogStatus;
+  exitCode: number;
+}
+
+export interface TaskParam {
+  name: string;
+  default: string;
+  description?: string;
+}
+
+export type ParamMode = "env" | "args" | "both";
+
+export interface WebhookConfig {
+  enabled: boolean; // 是否允许通过 Webhook 触发
+  token?: string; // 触发密钥，由后端生成
+}
+
+export interface RetryPolicy {
+  maxAttempts: number; // 最大尝试次数（含首次）
+  backoff: BackoffType;
+  delay: number; // 首次重试间隔（秒）
+  maxDelay: number; // 指数退避的最大间隔（秒）
+  retryOnExitCodes?: number[]; // 仅这些退出码才重试
 }
 
 export interface Task {
   id: string;
   name: string;
   scriptId: string; // 关联的脚本ID
+  triggerType?: TriggerType; // 触发方式，为空表示定时调度
+  triggerDelay?: number; // 启动或唤醒后延迟执行的时间（秒）
   scheduleType: ScheduleType;
   cron: string;
+  timezone?: string; // IANA 时区，为空时使用本机时区
   timeConfig: TimeConfig;
+  runAt?: string; // 一次性任务的执行时间
+  fileWatch?: FileWatch; // 文件监视触发配置
+  calendars?: string[]; // 排除日历ID
+  completedAt?: string; // 一次性任务的完成时间
+  startAt?: string; // 生效时间
+  endAt?: string; // 失效时间，之后自动停用
+  maxRuns?: number; // 最多定时执行次数，0 表示不限制
+  runCount?: number; // 已定时执行的次数
   status: TaskStatus;
+  notifyOn?: NotifyPolicy; // 通知策略
+  overlapPolicy?: OverlapPolicy; // 重叠执行策略
+  timeout?: number; // 超时时间（秒），0 表示使用脚本或全局设置
+  jitter?: number; // 随机延迟窗口（秒）
+  retry?: RetryPolicy; // 失败重试策略
+  onSuccess?: string[]; // 成功后触发的下游任务ID
+  onFailure?: string[]; // 失败后触发的下游任务ID
+  onComplete?: string[]; // 结束后触发的下游任务ID
+  misfirePolicy?: MisfirePolicy; // 错过执行时的补跑策略
+  misfireLimit?: number; // 补跑全部时的最大次数
+  watchdog?: WatchdogRule; // 看门狗告警规则
+  webhook?: WebhookConfig; // Webhook 触发配置
+  conditions?: Condition[]; // 前置条件，全部成立时才执行
+  params?: TaskParam[]; // 参数定义，手动执行可覆盖
+  paramMode?: ParamMode; // 参数传递方式，为空表示环境变量
+  workflow?: Workflow | null; // 工作流配置，有步骤时不使用 scriptId
   description: string;
   createdAt: string;
   updatedAt: string;
@@ -52,6 +194,17 @@ export interface TaskLog {
   output: string;
   error: string;
   success: boolean;
+  status?: LogStatus;
+  exitCode?: number;
+  attempt?: number; // 第几次尝试
+  parentRunId?: string; // 重试时指向首次执行的日志ID
+  trigger?: TriggerSource; // 触发来源
+  triggeredBy?: string; // 上游执行的日志ID
+  triggerInfo?: string; // 触发详情，如变化的文件路径
+  scheduledAt?: string; // 计划触发时间
+  jitterDelay?: number; // 本次触发的随机延迟（秒）
+  params?: Record<string, string>; // 本次执行的实际参数
+  steps?: StepLog[]; // 工作流各步骤的执行日志
 }
 
 export interface NotifierConfig {
@@ -70,5 +223,40 @@ export interface Stats {
   totalLogs: number;
   successLogs: number;
   failedLogs: number;
+  skippedLogs: number;
   schedulerRunning: boolean;
+  schedulerPaused: boolean;
+  pausedUntil?: string; // 自动恢复时间
+}
+
+export interface RunInfo {
+  runId: string;
+  taskId: string;
+  taskName: string;
+  queuedAt: string;
+  startedAt?: string;
+}
+
+export interface ExclusionWindow {
+  name: string;
+  weekdays: number[]; // 生效的星期几，为空表示每天
+  start: string; // HH:MM，为空表示全天
+  end: string; // HH:MM
+}
+
+export interface ExclusionRange {
+  name: string;
+  start: string;
+  end: string;
+}
+
+export interface Calendar {
+  id: string;
+  name: string;
+  description: string;
+  dates: string[]; // YYYY-MM-DD
+  windows: ExclusionWindow[];
+  ranges: ExclusionRange[];
+  createdAt: string;
+  updatedAt: string;
 }
@@ -1,7 +1,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {calendar} from '../models';
+import {executor} from '../models';
 import {main} from '../models';
+import {scheduler} from '../models';
+
+export function CancelRun(arg1:string):Promise<void>;
+
+export function CreateCalendar(arg1:models.Calendar):Promise<void>;
 
 export function CreateNotifierConfig(arg1:models.NotifierConfig):Promise<void>;
 
@@ -9,6 +16,8 @@ export function CreateScript(arg1:models.Script):Promise<void>;
 
 export function CreateTask(arg1:models.Task):Promise<void>;
 
+export function DeleteCalendar(arg1:string):Promise<void>;
+
 export function DeleteEnvironmentVariable(arg1:string):Promise<void>;
 
 export function DeleteNotifierConfig(arg1:string):Promise<void>;
@@ -17,6 +26,10 @@ export function DeleteScript(arg1:string):Promise<void>;
 
 export function DeleteTask(arg1:string):Promise<void>;
 
+export function GenerateCron(arg1:string,arg2:models.TimeConfig):Promise<string>;
+
+export function GetAllCalendars():Promise<Array<models.Calendar>>;
+
 export function GetAllLogs(arg1:number):Promise<Array<models.TaskLog>>;
 
 export function GetAllNotifierConfigs():Promise<Array<models.NotifierConfig>>;
@@ -29,24 +42,44 @@ export function GetDependencies():Promise<Array<main.Dependency>>;
 
 export function GetEnvironmentVariables():Promise<Record<string, string>>;
 
+export function GetQueuedRuns():Promise<Array<scheduler.RunInfo>>;
+
+export function GetRunOutput(arg1:string):Promise<executor.LiveOutput>;
+
+export function GetRunningRuns():Promise<Array<scheduler.RunInfo>>;
+
 export function GetScript(arg1:string):Promise<models.Script>;
 
 export function GetScriptsDir():Promise<string>;
 
+export function GetSettings():Promise<models.Settings>;
+
 export function GetStats():Promise<Record<string, any>>;
 
 export function GetTask(arg1:string):Promise<models.Task>;
 
 export function GetTaskLogs(arg1:string,arg2:number):Promise<Array<models.TaskLog>>;
 
+export function GetWebhookURL(arg1:string):Promise<string>;
+
+export function ImportCalendarICS(arg1:string):Promise<calendar.ImportResult>;
+
 export function InstallDependency(arg1:string,arg2:string):Promise<void>;
 
 export function OpenDirectory(arg1:string):Promise<void>;
 
+export function PauseScheduler(arg1:any):Promise<void>;
+
+export function RegenerateWebhookToken(arg1:string):Promise<string>;
+
+export function ResumeScheduler():Promise<void>;
+
 export function RunScript(arg1:string,arg2:boolean):Promise<void>;
 
 export function RunTaskNow(arg1:string):Promise<void>;
 
+export function RunTaskWithParams(arg1:string,arg2:Record<string, string>):Promise<void>;
+
 export function SelectFile():Promise<string>;
 
 export function SetEnvironmentVariable(arg1:string,arg2:string):Promise<void>;
@@ -55,10 +88,14 @@ export function ToggleTaskStatus(arg1:string):Promise<void>;
 
 export function UninstallDependency(arg1:string,arg2:string):Promise<void>;
 
+export function UpdateCalendar(arg1:models.Calendar):Promise<void>;
+
 export function UpdateNotifierConfig(arg1:models.NotifierConfig):Promise<void>;
 
 export function UpdateScript(arg1:models.Script):Promise<void>;
 
+export function UpdateSettings(arg1:models.Settings):Promise<void>;
+
 export function UpdateTask(arg1:models.Task):Promise<void>;
 
-export function ValidateCron(arg1:string):Promise<boolean>;
+export function ValidateCron(arg1:string,arg2:string,arg3:number):Promise<scheduler.CronValidation>;
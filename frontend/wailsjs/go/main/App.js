@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelRun(arg1) {
+  return window['go']['main']['App']['CancelRun'](arg1);
+}
+
+export function CreateCalendar(arg1) {
+  return window['go']['main']['App']['CreateCalendar'](arg1);
+}
+
 export function CreateNotifierConfig(arg1) {
   return window['go']['main']['App']['CreateNotifierConfig'](arg1);
 }
@@ -14,6 +22,10 @@ export function CreateTask(arg1) {
   return window['go']['main']['App']['CreateTask'](arg1);
 }
 
+export function DeleteCalendar(arg1) {
+  return window['go']['main']['App']['DeleteCalendar'](arg1);
+}
+
 export function DeleteEnvironmentVariable(arg1) {
   return window['go']['main']['App']['DeleteEnvironmentVariable'](arg1);
 }
@@ -30,6 +42,14 @@ export function DeleteTask(arg1) {
   return window['go']['main']['App']['DeleteTask'](arg1);
 }
 
+export function GenerateCron(arg1,arg2) {
+  return window['go']['main']['App']['GenerateCron'](arg1,arg2);
+}
+
+export function GetAllCalendars() {
+  return window['go']['main']['App']['GetAllCalendars']();
+}
+
 export function GetAllLogs(arg1) {
   return window['go']['main']['App']['GetAllLogs'](arg1);
 }
@@ -54,6 +74,18 @@ export function GetEnvironmentVariables() {
   return window['go']['main']['App']['GetEnvironmentVariables']();
 }
 
+export function GetQueuedRuns() {
+  return window['go']['main']['App']['GetQueuedRuns']();
+}
+
+export function GetRunOutput(arg1) {
+  return window['go']['main']['App']['GetRunOutput'](arg1);
+}
+
+export function GetRunningRuns() {
+  return window['go']['main']['App']['GetRunningRuns']();
+}
+
 export function GetScript(arg1) {
   return window['go']['main']['App']['GetScript'](arg1);
 }
@@ -62,6 +94,10 @@ export function GetScriptsDir() {
   return window['go']['main']['App']['GetScriptsDir']();
 }
 
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
 export function GetStats() {
   return window['go']['main']['App']['GetStats']();
 }
@@ -74,6 +110,14 @@ export function GetTaskLogs(arg1, arg2) {
   return window['go']['main']['App']['GetTaskLogs'](arg1, arg2);
 }
 
+export function GetWebhookURL(arg1) {
+  return window['go']['main']['App']['GetWebhookURL'](arg1);
+}
+
+export function ImportCalendarICS(arg1) {
+  return window['go']['main']['App']['ImportCalendarICS'](arg1);
+}
+
 export function InstallDependency(arg1, arg2) {
   return window['go']['main']['App']['InstallDependency'](arg1, arg2);
 }
@@ -82,6 +126,18 @@ export function OpenDirectory(arg1) {
   return window['go']['main']['App']['OpenDirectory'](arg1);
 }
 
+export function PauseScheduler(arg1) {
+  return window['go']['main']['App']['PauseScheduler'](arg1);
+}
+
+export function RegenerateWebhookToken(arg1) {
+  return window['go']['main']['App']['RegenerateWebhookToken'](arg1);
+}
+
+export function ResumeScheduler() {
+  return window['go']['main']['App']['ResumeScheduler']();
+}
+
 export function RunScript(arg1, arg2) {
   return window['go']['main']['App']['RunScript'](arg1, arg2);
 }
@@ -90,6 +146,10 @@ export function RunTaskNow(arg1) {
   return window['go']['main']['App']['RunTaskNow'](arg1);
 }
 
+export function RunTaskWithParams(arg1,arg2) {
+  return window['go']['main']['App']['RunTaskWithParams'](arg1,arg2);
+}
+
 export function SelectFile() {
   return window['go']['main']['App']['SelectFile']();
 }
@@ -106,6 +166,10 @@ export function UninstallDependency(arg1, arg2) {
   return window['go']['main']['App']['UninstallDependency'](arg1, arg2);
 }
 
+export function UpdateCalendar(arg1) {
+  return window['go']['main']['App']['UpdateCalendar'](arg1);
+}
+
 export function UpdateNotifierConfig(arg1) {
   return window['go']['main']['App']['UpdateNotifierConfig'](arg1);
 }
@@ -114,10 +178,14 @@ export function UpdateScript(arg1) {
   return window['go']['main']['App']['UpdateScript'](arg1);
 }
 
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
 export function UpdateTask(arg1) {
   return window['go']['main']['App']['UpdateTask'](arg1);
 }
 
-export function ValidateCron(arg1) {
-  return window['go']['main']['App']['ValidateCron'](arg1);
+export function ValidateCron(arg1,arg2,arg3) {
+  return window['go']['main']['App']['ValidateCron'](arg1,arg2,arg3);
 }
@@ -1,3 +1,81 @@
+export namespace calendar {
+	
+	export class ImportResult {
+	    events: number;
+	    dates: number;
+	    ranges: number;
+	    unsupported: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.events = source["events"];
+	        this.dates = source["dates"];
+	        this.ranges = source["ranges"];
+	        this.unsupported = source["unsupported"];
+	    }
+	}
+
+}
+
+export namespace executor {
+	
+	export class OutputLine {
+	    stream: string;
+	    line: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OutputLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stream = source["stream"];
+	        this.line = source["line"];
+	    }
+	}
+	export class LiveOutput {
+	    runId: string;
+	    lines: OutputLine[];
+	    seq: number;
+	    running: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LiveOutput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.runId = source["runId"];
+	        this.lines = this.convertValues(source["lines"], OutputLine);
+	        this.seq = source["seq"];
+	        this.running = source["running"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
 	export class Dependency {
@@ -21,6 +99,106 @@ export namespace main {
 
 export namespace models {
 	
+	export class Calendar {
+	    id: string;
+	    name: string;
+	    description: string;
+	    dates: string[];
+	    windows: ExclusionWindow[];
+	    ranges: ExclusionRange[];
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Calendar(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.dates = source["dates"];
+	        this.windows = this.convertValues(source["windows"], ExclusionWindow);
+	        this.ranges = this.convertValues(source["ranges"], ExclusionRange);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExclusionRange {
+	    name: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExclusionRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExclusionWindow {
+	    name: string;
+	    weekdays: number[];
+	    start: string;
+	    end: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExclusionWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.weekdays = source["weekdays"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
 	export class NotifierConfig {
 	    id: string;
 	    type: string;
@@ -116,12 +294,36 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class Settings {
+	    maxConcurrentTasks: number;
+	    logsRetentionDays: number;
+	    enableNotifications: boolean;
+	    defaultTimeout: number;
+	    enableWebhooks: boolean;
+	    webhookAddr: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxConcurrentTasks = source["maxConcurrentTasks"];
+	        this.logsRetentionDays = source["logsRetentionDays"];
+	        this.enableNotifications = source["enableNotifications"];
+	        this.defaultTimeout = source["defaultTimeout"];
+	        this.enableWebhooks = source["enableWebhooks"];
+	        this.webhookAddr = source["webhookAddr"];
+	    }
+	}
 	export class TimeConfig {
 	    hour: number;
 	    minute: number;
 	    weekday: number;
 	    monthday: number;
 	    weekdays: number[];
+	    interval: number;
+	    nth: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new TimeConfig(source);
@@ -134,6 +336,8 @@ export namespace models {
 	        this.weekday = source["weekday"];
 	        this.monthday = source["monthday"];
 	        this.weekdays = source["weekdays"];
+	        this.interval = source["interval"];
+	        this.nth = source["nth"];
 	    }
 	}
 	export class Task {
@@ -243,3 +447,101 @@ export namespace models {
 
 }
 
+export namespace scheduler {
+	
+	export class CronError {
+	    message: string;
+	    field: string;
+	    position: number;
+	    length: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CronError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.field = source["field"];
+	        this.position = source["position"];
+	        this.length = source["length"];
+	    }
+	}
+	export class CronValidation {
+	    valid: boolean;
+	    error?: CronError;
+	    // Go type: time
+	    nextRuns: any[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CronValidation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.valid = source["valid"];
+	        this.error = this.convertValues(source["error"], CronError);
+	        this.nextRuns = this.convertValues(source["nextRuns"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RunInfo {
+	    runId: string;
+	    taskId: string;
+	    taskName: string;
+	    // Go type: time
+	    queuedAt: any;
+	    // Go type: time
+	    startedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new RunInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.runId = source["runId"];
+	        this.taskId = source["taskId"];
+	        this.taskName = source["taskName"];
+	        this.queuedAt = this.convertValues(source["queuedAt"], null);
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
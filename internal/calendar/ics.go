@@ -0,0 +1,351 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tempo/internal/models"
+)
+
+// recurrenceHorizon 没有 COUNT/UNTIL 的重复事件展开的年数
+const recurrenceHorizon = 5
+
+// ImportResult ICS 导入结果
+type ImportResult struct {
+	Events      int `json:"events"`      // 读取的事件数
+	Dates       int `json:"dates"`       // 新增的排除日期数
+	Ranges      int `json:"ranges"`      // 新增的排除时间段数
+	Unsupported int `json:"unsupported"` // 重复规则不支持、只导入了首次发生的事件数
+}
+
+// icsProperty ICS 属性行
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// icsEvent VEVENT 中用到的属性
+type icsEvent struct {
+	summary string
+	start   *icsProperty
+	end     *icsProperty
+	rrule   string
+	status  string
+}
+
+// ImportICS 解析 ICS 文件，将其中的事件合并到日历：全天事件导入为排除日期，其他事件导入为排除时间段
+// 支持 FREQ=YEARLY 的重复事件（如节假日），其他重复规则只导入首次发生
+func ImportICS(cal *models.Calendar, data []byte) (*ImportResult, error) {
+	events, err := parseEvents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Events: len(events)}
+	dates := make(map[string]bool, len(cal.Dates))
+	for _, date := range cal.Dates {
+		dates[date] = true
+	}
+	ranges := make(map[string]bool, len(cal.Ranges))
+	for _, r := range cal.Ranges {
+		ranges[rangeKey(r)] = true
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		if event.start == nil || strings.EqualFold(event.status, "CANCELLED") {
+			continue
+		}
+
+		start, allDay, err := parseICSTime(event.start)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.summary, err)
+		}
+		end, err := eventEnd(event, start, allDay)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.summary, err)
+		}
+
+		occurrences, supported, err := expandYearly(event.rrule, start, now)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.summary, err)
+		}
+		if !supported {
+			result.Unsupported++
+		}
+
+		for _, occurrence := range occurrences {
+			shifted := end.Add(occurrence.Sub(start))
+			if allDay {
+				for d := occurrence; d.Before(shifted); d = d.AddDate(0, 0, 1) {
+					date := d.Format(time.DateOnly)
+					if !dates[date] {
+						dates[date] = true
+						cal.Dates = append(cal.Dates, date)
+						result.Dates++
+					}
+				}
+				continue
+			}
+
+			r := models.ExclusionRange{Name: event.summary, Start: occurrence, End: shifted}
+			if !ranges[rangeKey(r)] {
+				ranges[rangeKey(r)] = true
+				cal.Ranges = append(cal.Ranges, r)
+				result.Ranges++
+			}
+		}
+	}
+
+	sort.Strings(cal.Dates)
+	sort.Slice(cal.Ranges, func(i, j int) bool {
+		return cal.Ranges[i].Start.Before(cal.Ranges[j].Start)
+	})
+	return result, nil
+}
+
+// parseEvents 读取 ICS 中的所有 VEVENT
+func parseEvents(data []byte) ([]*icsEvent, error) {
+	lines := unfoldLines(data)
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar file")
+	}
+
+	var events []*icsEvent
+	var current *icsEvent
+	depth := 0 // VEVENT 内嵌套组件（如 VALARM）的层数
+
+	for _, line := range lines {
+		prop, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			current = &icsEvent{}
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current != nil {
+				events = append(events, current)
+			}
+			current = nil
+			depth = 0
+		case current == nil:
+			continue
+		case prop.name == "BEGIN":
+			depth++
+		case prop.name == "END":
+			depth--
+		case depth > 0:
+			continue
+		case prop.name == "SUMMARY":
+			current.summary = unescapeText(prop.value)
+		case prop.name == "DTSTART":
+			current.start = prop
+		case prop.name == "DTEND":
+			current.end = prop
+		case prop.name == "RRULE":
+			current.rrule = prop.value
+		case prop.name == "STATUS":
+			current.status = prop.value
+		}
+	}
+	return events, nil
+}
+
+// unfoldLines 按 RFC 5545 展开折行
+func unfoldLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseProperty 解析 NAME;PARAM=VALUE:VALUE 格式的属性行
+func parseProperty(line string) (*icsProperty, bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return nil, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := &icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// parseICSTime 解析 DTSTART/DTEND，返回时间及是否为全天（DATE 类型）
+func parseICSTime(prop *icsProperty) (time.Time, bool, error) {
+	value := prop.value
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return t, false, nil
+}
+
+// eventEnd 返回事件结束时间，缺少 DTEND 时全天事件持续一天，其他事件持续一小时
+func eventEnd(event *icsEvent, start time.Time, allDay bool) (time.Time, error) {
+	if event.end == nil {
+		if allDay {
+			return start.AddDate(0, 0, 1), nil
+		}
+		return start.Add(time.Hour), nil
+	}
+
+	end, _, err := parseICSTime(event.end)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !end.After(start) {
+		if allDay {
+			return start.AddDate(0, 0, 1), nil
+		}
+		return start.Add(time.Hour), nil
+	}
+	return end, nil
+}
+
+// expandYearly 展开 FREQ=YEARLY 的重复规则；没有重复规则或规则不支持时只返回首次发生
+func expandYearly(rrule string, start, now time.Time) ([]time.Time, bool, error) {
+	if rrule == "" {
+		return []time.Time{start}, true, nil
+	}
+
+	rule := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			rule[strings.ToUpper(key)] = strings.ToUpper(value)
+		}
+	}
+
+	// 只支持按年在 BYMONTH 指定的月份（默认为起始月份）重复起始日期
+	if rule["FREQ"] != "YEARLY" || rule["BYDAY"] != "" || rule["BYMONTHDAY"] != "" || rule["BYYEARDAY"] != "" {
+		return []time.Time{start}, false, nil
+	}
+
+	months := []int{int(start.Month())}
+	if value := rule["BYMONTH"]; value != "" {
+		months = months[:0]
+		for _, part := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 1 || n > 12 {
+				return nil, false, fmt.Errorf("invalid RRULE month %q", value)
+			}
+			months = append(months, n)
+		}
+		sort.Ints(months)
+	}
+
+	interval := 1
+	if value := rule["INTERVAL"]; value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, false, fmt.Errorf("invalid RRULE interval %q", value)
+		}
+		interval = n
+	}
+
+	count := -1
+	if value := rule["COUNT"]; value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, false, fmt.Errorf("invalid RRULE count %q", value)
+		}
+		count = n
+	}
+
+	until := time.Date(now.Year()+recurrenceHorizon, time.December, 31, 23, 59, 59, 0, start.Location())
+	if value := rule["UNTIL"]; value != "" {
+		prop := &icsProperty{value: value, params: map[string]string{}}
+		t, _, err := parseICSTime(prop)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid RRULE until %q", value)
+		}
+		until = t
+	}
+
+	// 起始时间不符合规则时仍作为第一次发生
+	var occurrences []time.Time
+	if !slices.Contains(months, int(start.Month())) {
+		occurrences = append(occurrences, start)
+	}
+
+	// COUNT 只计实际发生的次数
+	for year := start.Year(); count < 0 || len(occurrences) < count; year += interval {
+		if time.Date(year, time.January, 1, 0, 0, 0, 0, start.Location()).After(until) {
+			break
+		}
+		for _, month := range months {
+			t := time.Date(year, time.Month(month), start.Day(),
+				start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+			// 2 月 29 日在平年、31 日在小月不发生
+			if t.Day() != start.Day() || t.Before(start) {
+				continue
+			}
+			if t.After(until) || (count >= 0 && len(occurrences) >= count) {
+				break
+			}
+			occurrences = append(occurrences, t)
+		}
+	}
+	return occurrences, true, nil
+}
+
+// unescapeText 还原 TEXT 类型中的转义字符
+func unescapeText(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// rangeKey 排除时间段的去重键
+func rangeKey(r models.ExclusionRange) string {
+	return r.Start.UTC().Format(time.RFC3339) + "/" + r.End.UTC().Format(time.RFC3339)
+}
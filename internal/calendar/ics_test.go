@@ -0,0 +1,111 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tempo/internal/models"
+)
+
+func TestExpandYearly(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.Local)
+	newYear := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.Local)
+	leapDay := time.Date(2020, time.February, 29, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		rrule       string
+		start       time.Time
+		want        []string
+		unsupported bool
+		wantErr     bool
+	}{
+		{name: "no rule", start: newYear, want: []string{"2020-01-01"}},
+		{
+			name:  "horizon",
+			rrule: "FREQ=YEARLY",
+			start: newYear,
+			want: []string{"2020-01-01", "2021-01-01", "2022-01-01", "2023-01-01", "2024-01-01",
+				"2025-01-01", "2026-01-01", "2027-01-01", "2028-01-01", "2029-01-01"},
+		},
+		{name: "count", rrule: "FREQ=YEARLY;COUNT=3", start: newYear, want: []string{"2020-01-01", "2021-01-01", "2022-01-01"}},
+		{name: "until date", rrule: "FREQ=YEARLY;UNTIL=20220101", start: newYear, want: []string{"2020-01-01", "2021-01-01", "2022-01-01"}},
+		{name: "until before next", rrule: "FREQ=YEARLY;UNTIL=20211231", start: newYear, want: []string{"2020-01-01", "2021-01-01"}},
+		{name: "interval with count", rrule: "FREQ=YEARLY;INTERVAL=2;COUNT=3", start: newYear, want: []string{"2020-01-01", "2022-01-01", "2024-01-01"}},
+		{name: "count ends before until", rrule: "FREQ=YEARLY;COUNT=2;UNTIL=20300101", start: newYear, want: []string{"2020-01-01", "2021-01-01"}},
+		{name: "leap day count", rrule: "FREQ=YEARLY;COUNT=3", start: leapDay, want: []string{"2020-02-29", "2024-02-29", "2028-02-29"}},
+		{name: "leap day until", rrule: "FREQ=YEARLY;UNTIL=20270101", start: leapDay, want: []string{"2020-02-29", "2024-02-29"}},
+		{name: "leap day horizon", rrule: "FREQ=YEARLY", start: leapDay, want: []string{"2020-02-29", "2024-02-29", "2028-02-29"}},
+		{name: "bymonth", rrule: "FREQ=YEARLY;BYMONTH=1;COUNT=2", start: newYear, want: []string{"2020-01-01", "2021-01-01"}},
+		{name: "bymonth multiple", rrule: "FREQ=YEARLY;BYMONTH=7,1;COUNT=4", start: newYear, want: []string{"2020-01-01", "2020-07-01", "2021-01-01", "2021-07-01"}},
+		{name: "bymonth other month", rrule: "FREQ=YEARLY;BYMONTH=3;UNTIL=20220101", start: newYear, want: []string{"2020-01-01", "2020-03-01", "2021-03-01"}},
+		{name: "bymonth skips short months", rrule: "FREQ=YEARLY;BYMONTH=1,2,3,4;COUNT=3", start: time.Date(2020, time.January, 31, 0, 0, 0, 0, time.Local), want: []string{"2020-01-31", "2020-03-31", "2021-01-31"}},
+		{name: "bymonth interval", rrule: "FREQ=YEARLY;INTERVAL=2;BYMONTH=1,6;UNTIL=20221231", start: newYear, want: []string{"2020-01-01", "2020-06-01", "2022-01-01", "2022-06-01"}},
+		{name: "weekly", rrule: "FREQ=WEEKLY;COUNT=3", start: newYear, want: []string{"2020-01-01"}, unsupported: true},
+		{name: "byday", rrule: "FREQ=YEARLY;BYDAY=1MO", start: newYear, want: []string{"2020-01-01"}, unsupported: true},
+		{name: "invalid count", rrule: "FREQ=YEARLY;COUNT=0", start: newYear, wantErr: true},
+		{name: "invalid interval", rrule: "FREQ=YEARLY;INTERVAL=x", start: newYear, wantErr: true},
+		{name: "invalid bymonth", rrule: "FREQ=YEARLY;BYMONTH=13", start: newYear, wantErr: true},
+		{name: "invalid until", rrule: "FREQ=YEARLY;UNTIL=2020", start: newYear, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences, supported, err := expandYearly(tt.rrule, tt.start, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", occurrences)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if supported == tt.unsupported {
+				t.Errorf("supported = %v, want %v", supported, !tt.unsupported)
+			}
+
+			got := make([]string, len(occurrences))
+			for i, occurrence := range occurrences {
+				got[i] = occurrence.Format(time.DateOnly)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportICSYearlyLeapDay(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Leap day",
+		"DTSTART;VALUE=DATE:20200229",
+		"RRULE:FREQ=YEARLY;COUNT=2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Maintenance",
+		"DTSTART:20200301T020000Z",
+		"DTEND:20200301T040000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal := &models.Calendar{Dates: []string{"2020-02-29"}}
+	result, err := ImportICS(cal, []byte(data))
+	if err != nil {
+		t.Fatalf("ImportICS: %v", err)
+	}
+
+	if result.Events != 2 || result.Dates != 1 || result.Ranges != 1 || result.Unsupported != 0 {
+		t.Errorf("result = %+v, want 2 events, 1 new date, 1 range", *result)
+	}
+	if strings.Join(cal.Dates, ",") != "2020-02-29,2024-02-29" {
+		t.Errorf("dates = %v", cal.Dates)
+	}
+	if len(cal.Ranges) != 1 || cal.Ranges[0].End.Sub(cal.Ranges[0].Start) != 2*time.Hour {
+		t.Errorf("ranges = %v", cal.Ranges)
+	}
+}
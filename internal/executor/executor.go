@@ -1,13 +1,14 @@
 package executor
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"tempo/internal/models"
 	"time"
 )
@@ -16,6 +17,10 @@ import (
 type Executor struct {
 	scriptsDir string
 	dataDir    string
+
+	mu            sync.Mutex
+	runs          map[string]*runningExecution
+	outputHandler OutputHandler
 }
 
 // New 创建执行器
@@ -30,38 +35,79 @@ func New(scriptsDir string) (*Executor, error) {
 	return &Executor{
 		scriptsDir: scriptsDir,
 		dataDir:    dataDir,
+		runs:       make(map[string]*runningExecution),
 	}, nil
 }
 
+// RunInput 单次执行的额外输入
+type RunInput struct {
+	Env  map[string]string // 追加的环境变量，优先于自定义环境变量
+	Args []string          // 追加在脚本路径后的命令行参数
+
+	ForwardTo  string // 输出同时转发给的运行ID（由 Track 登记），如工作流步骤转发给工作流
+	ForwardTag string // 转发的每行前添加的标记，如步骤标识
+}
+
 // ExecuteResult 执行结果
 type ExecuteResult struct {
-	Output  string
-	Error   string
-	Success bool
+	Output    string
+	Error     string
+	Success   bool
+	Cancelled bool
+	TimedOut  bool
+	ExitCode  int
 }
 
 // Execute 执行脚本（通用方法）
-func (e *Executor) Execute(scriptType models.ScriptType, scriptPath, scriptCode string) *ExecuteResult {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+// ctx 的截止时间即执行超时时间，runID 用于取消运行中的执行
+func (e *Executor) Execute(ctx context.Context, runID string, scriptType models.ScriptType, scriptPath, scriptCode string, input RunInput) *ExecuteResult {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	execution := e.register(runID, cancel)
+	defer e.emitDone(runID)
+	defer e.unregister(runID)
+
 	// 准备脚本文件
 	path, err := e.prepareScript(scriptType, scriptPath, scriptCode)
 	if err != nil {
 		return &ExecuteResult{
-			Error:   err.Error(),
-			Success: false,
+			Error:    err.Error(),
+			Success:  false,
+			ExitCode: -1,
 		}
 	}
 
 	// 执行脚本
-	output, err := e.executeScript(ctx, scriptType, path)
+	output, err := e.executeScript(ctx, runID, execution, scriptType, path, input)
+	exitCode := exitCodeOf(err)
+
+	if execution.cancelled.Load() {
+		return &ExecuteResult{
+			Output:    output,
+			Error:     "cancelled by user",
+			Success:   false,
+			Cancelled: true,
+			ExitCode:  exitCode,
+		}
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &ExecuteResult{
+			Output:   output,
+			Error:    "execution timed out",
+			Success:  false,
+			TimedOut: true,
+			ExitCode: exitCode,
+		}
+	}
 
 	if err != nil {
 		return &ExecuteResult{
-			Output:  output,
-			Error:   err.Error(),
-			Success: false,
+			Output:   output,
+			Error:    err.Error(),
+			Success:  false,
+			ExitCode: exitCode,
 		}
 	}
 
@@ -71,19 +117,19 @@ func (e *Executor) Execute(scriptType models.ScriptType, scriptPath, scriptCode
 	}
 }
 
-// ExecuteTask 执行任务（通过脚本ID）
-func (e *Executor) ExecuteTask(ctx context.Context, task *models.Task, script *models.Script) (*models.TaskLog, error) {
+// ExecuteTask 执行任务（通过脚本ID），runID 作为日志ID
+func (e *Executor) ExecuteTask(ctx context.Context, runID string, task *models.Task, script *models.Script, input RunInput) (*models.TaskLog, error) {
 	startTime := time.Now()
 
 	log := &models.TaskLog{
-		ID:        fmt.Sprintf("log_%d", time.Now().UnixNano()),
+		ID:        runID,
 		TaskID:    task.ID,
 		TaskName:  task.Name,
 		StartTime: startTime,
 	}
 
 	// 执行脚本
-	result := e.Execute(script.ScriptType, script.ScriptPath, script.ScriptCode)
+	result := e.Execute(ctx, runID, script.ScriptType, script.ScriptPath, script.ScriptCode, input)
 
 	endTime := time.Now()
 	log.EndTime = endTime
@@ -91,10 +137,38 @@ func (e *Executor) ExecuteTask(ctx context.Context, task *models.Task, script *m
 	log.Output = result.Output
 	log.Error = result.Error
 	log.Success = result.Success
+	log.Status = result.Status()
+	log.ExitCode = result.ExitCode
 
 	return log, nil
 }
 
+// exitCodeOf 从执行错误中提取退出码，未能正常退出时返回 -1
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// Status 返回执行结果对应的日志状态
+func (r *ExecuteResult) Status() models.LogStatus {
+	switch {
+	case r.Success:
+		return models.LogStatusSuccess
+	case r.Cancelled:
+		return models.LogStatusCancelled
+	case r.TimedOut:
+		return models.LogStatusTimeout
+	default:
+		return models.LogStatusFailed
+	}
+}
+
 // prepareScript 准备脚本文件
 func (e *Executor) prepareScript(scriptType models.ScriptType, scriptPath, scriptCode string) (string, error) {
 	// 如果提供了脚本路径，直接使用
@@ -132,7 +206,7 @@ func (e *Executor) prepareScript(scriptType models.ScriptType, scriptPath, scrip
 }
 
 // executeScript 执行脚本
-func (e *Executor) executeScript(ctx context.Context, scriptType models.ScriptType, scriptPath string) (string, error) {
+func (e *Executor) executeScript(ctx context.Context, runID string, execution *runningExecution, scriptType models.ScriptType, scriptPath string, input RunInput) (string, error) {
 	var cmd *exec.Cmd
 
 	switch scriptType {
@@ -171,6 +245,7 @@ func (e *Executor) executeScript(ctx context.Context, scriptType models.ScriptTy
 	default:
 		return "", fmt.Errorf("unsupported script type: %s", scriptType)
 	}
+	cmd.Args = append(cmd.Args, input.Args...)
 
 	// 设置工作目录为脚本目录，使脚本能访问本地依赖（如 node_modules）
 	cmd.Dir = e.scriptsDir
@@ -187,16 +262,53 @@ func (e *Executor) executeScript(ctx context.Context, scriptType models.ScriptTy
 		fmt.Sprintf("NODE_PATH=%s/node_modules", e.scriptsDir),
 		fmt.Sprintf("PYTHONPATH=%s", e.scriptsDir),
 	)
+	for key, value := range input.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
 	cmd.Env = env
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// 在独立进程组中运行，取消时先发送终止信号，宽限期后强制结束整个进程组
+	setProcessGroup(cmd)
+	done := make(chan struct{})
+	defer close(done)
+	cmd.Cancel = func() error {
+		err := terminateProcessGroup(cmd)
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(killGracePeriod):
+				killProcessGroup(cmd)
+			}
+		}()
+		return err
+	}
+	cmd.WaitDelay = 2 * killGracePeriod
+
+	// 按行实时转发输出，同时保留完整输出用于日志
+	// exec 为两个 Writer 分别启动复制协程，emit 需要自行保证并发安全
+	var emitMu sync.Mutex
+	emit := func(stream, line string) {
+		emitMu.Lock()
+		defer emitMu.Unlock()
+		e.emitOutput(runID, execution, stream, line)
+		if input.ForwardTo != "" {
+			if input.ForwardTag != "" {
+				line = "[" + input.ForwardTag + "] " + line
+			}
+			e.forwardOutput(input.ForwardTo, stream, line)
+		}
+	}
+	stdout := &lineWriter{stream: StreamStdout, emit: emit}
+	stderr := &lineWriter{stream: StreamStderr, emit: emit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	err := cmd.Run()
+	stdout.flush()
+	stderr.flush()
 
 	output := stdout.String()
-	if stderr.Len() > 0 {
+	if stderr.full.Len() > 0 {
 		output += "\n[STDERR]\n" + stderr.String()
 	}
 
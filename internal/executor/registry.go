@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// killGracePeriod 发送终止信号后等待进程退出的时间，超时后强制结束
+const killGracePeriod = 5 * time.Second
+
+// runningExecution 运行中的执行
+type runningExecution struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+	output    liveBuffer
+}
+
+// register 登记运行中的执行
+func (e *Executor) register(runID string, cancel context.CancelFunc) *runningExecution {
+	execution := &runningExecution{cancel: cancel}
+	if runID == "" {
+		return execution
+	}
+
+	e.mu.Lock()
+	e.runs[runID] = execution
+	e.mu.Unlock()
+	return execution
+}
+
+// Track 登记不直接执行脚本的运行（如工作流），使其可以接收转发的输出并被实时订阅
+// 返回结束登记的函数，调用后发送执行结束事件
+func (e *Executor) Track(runID string) func() {
+	e.register(runID, func() {})
+	return func() {
+		e.unregister(runID)
+		e.emitDone(runID)
+	}
+}
+
+// unregister 移除运行中的执行
+func (e *Executor) unregister(runID string) {
+	e.mu.Lock()
+	delete(e.runs, runID)
+	e.mu.Unlock()
+}
+
+// Cancel 取消运行中的执行，脚本及其子进程会先收到 SIGTERM，宽限期后被强制结束
+func (e *Executor) Cancel(runID string) error {
+	e.mu.Lock()
+	execution, ok := e.runs[runID]
+	e.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+
+	execution.cancelled.Store(true)
+	execution.cancel()
+	return nil
+}
+
+// IsRunning 检查执行是否仍在运行
+func (e *Executor) IsRunning(runID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, ok := e.runs[runID]
+	return ok
+}
@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// 输出流名称
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// maxLiveLines 运行中输出缓冲保留的最大行数
+const maxLiveLines = 5000
+
+// OutputLine 一行脚本输出
+type OutputLine struct {
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+}
+
+// OutputEvent 实时输出事件，Done 为 true 表示执行已结束
+type OutputEvent struct {
+	RunID  string `json:"runId"`
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+	Seq    int    `json:"seq"` // 行序号，从 1 开始，用于与快照合并去重
+	Done   bool   `json:"done"`
+}
+
+// OutputHandler 实时输出回调
+type OutputHandler func(event OutputEvent)
+
+// LiveOutput 运行中执行的输出快照
+type LiveOutput struct {
+	RunID   string       `json:"runId"`
+	Lines   []OutputLine `json:"lines"`
+	Seq     int          `json:"seq"` // 快照中最后一行的序号，序号不大于它的事件已包含在快照中
+	Running bool         `json:"running"`
+}
+
+// liveBuffer 运行中执行的输出缓冲
+type liveBuffer struct {
+	mu    sync.Mutex
+	lines []OutputLine
+	total int // 累计追加的行数，即最后一行的序号
+}
+
+// append 追加一行输出并返回其序号，超出上限时丢弃最早的行
+func (b *liveBuffer) append(line OutputLine) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, line)
+	if len(b.lines) > maxLiveLines {
+		b.lines = b.lines[len(b.lines)-maxLiveLines:]
+	}
+	b.total++
+	return b.total
+}
+
+// snapshot 返回当前缓冲的副本及最后一行的序号
+func (b *liveBuffer) snapshot() ([]OutputLine, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := make([]OutputLine, len(b.lines))
+	copy(lines, b.lines)
+	return lines, b.total
+}
+
+// lineWriter 按行转发输出的 Writer，同时保留完整输出
+type lineWriter struct {
+	stream  string
+	full    bytes.Buffer
+	partial []byte
+	emit    func(stream, line string)
+}
+
+// Write 实现 io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.full.Write(p)
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.partial[:i], []byte("\r"))
+		w.emit(w.stream, string(line))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush 输出最后不以换行结尾的内容
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.stream, string(w.partial))
+		w.partial = nil
+	}
+}
+
+// String 返回完整输出
+func (w *lineWriter) String() string {
+	return w.full.String()
+}
+
+// SetOutputHandler 设置实时输出回调
+func (e *Executor) SetOutputHandler(handler OutputHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.outputHandler = handler
+}
+
+// emitOutput 记录并转发一行输出
+func (e *Executor) emitOutput(runID string, execution *runningExecution, stream, line string) {
+	seq := execution.output.append(OutputLine{Stream: stream, Line: line})
+
+	e.mu.Lock()
+	handler := e.outputHandler
+	e.mu.Unlock()
+
+	if handler != nil && runID != "" {
+		handler(OutputEvent{RunID: runID, Stream: stream, Line: line, Seq: seq})
+	}
+}
+
+// forwardOutput 将一行输出转发给另一个登记中的运行，该运行已结束时忽略
+func (e *Executor) forwardOutput(runID, stream, line string) {
+	e.mu.Lock()
+	execution, ok := e.runs[runID]
+	e.mu.Unlock()
+
+	if ok {
+		e.emitOutput(runID, execution, stream, line)
+	}
+}
+
+// emitDone 通知执行结束
+func (e *Executor) emitDone(runID string) {
+	e.mu.Lock()
+	handler := e.outputHandler
+	e.mu.Unlock()
+
+	if handler != nil && runID != "" {
+		handler(OutputEvent{RunID: runID, Done: true})
+	}
+}
+
+// Output 获取运行中执行的已输出内容
+func (e *Executor) Output(runID string) (*LiveOutput, error) {
+	e.mu.Lock()
+	execution, ok := e.runs[runID]
+	e.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("run not found: %s", runID)
+	}
+
+	lines, seq := execution.output.snapshot()
+	return &LiveOutput{
+		RunID:   runID,
+		Lines:   lines,
+		Seq:     seq,
+		Running: true,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar 排除日历，挂载到任务后落在排除范围内的定时触发会被跳过
+type Calendar struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Dates       []string          `json:"dates"`   // 排除的日期 (YYYY-MM-DD)，如法定节假日
+	Windows     []ExclusionWindow `json:"windows"` // 周期性排除窗口，如周末
+	Ranges      []ExclusionRange  `json:"ranges"`  // 指定的排除时间段，如从 ICS 导入的非全天事件
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// ExclusionWindow 每周重复的排除窗口
+type ExclusionWindow struct {
+	Name     string `json:"name"`
+	Weekdays []int  `json:"weekdays"` // 生效的星期几 (0-6, 0=周日)，为空表示每天
+	Start    string `json:"start"`    // 开始时间 (HH:MM)，为空表示全天
+	End      string `json:"end"`      // 结束时间 (HH:MM)，早于开始时间时表示跨越午夜
+}
+
+// ExclusionRange 一段确定的排除时间 [Start, End)
+type ExclusionRange struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Validate 校验日历内容
+func (c *Calendar) Validate() error {
+	for _, date := range c.Dates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+		}
+	}
+	for _, window := range c.Windows {
+		for _, day := range window.Weekdays {
+			if day < 0 || day > 6 {
+				return fmt.Errorf("invalid weekday %d in window %q: must be 0-6", day, window.Name)
+			}
+		}
+		if (window.Start == "") != (window.End == "") {
+			return fmt.Errorf("window %q must set both start and end, or neither", window.Name)
+		}
+		if window.Start != "" {
+			start, err := parseClock(window.Start)
+			if err != nil {
+				return fmt.Errorf("invalid start time in window %q: %w", window.Name, err)
+			}
+			end, err := parseClock(window.End)
+			if err != nil {
+				return fmt.Errorf("invalid end time in window %q: %w", window.Name, err)
+			}
+			if start == end {
+				return fmt.Errorf("window %q has the same start and end time", window.Name)
+			}
+		}
+	}
+	for _, r := range c.Ranges {
+		if !r.End.After(r.Start) {
+			return fmt.Errorf("range %q ends before it starts", r.Name)
+		}
+	}
+	return nil
+}
+
+// Excludes 判断时间 t（按任务时区表示）是否落在排除范围内，并返回命中的原因
+func (c *Calendar) Excludes(t time.Time) (bool, string) {
+	day := t.Format(time.DateOnly)
+	for _, date := range c.Dates {
+		if date == day {
+			return true, "date " + date
+		}
+	}
+
+	for _, window := range c.Windows {
+		if window.contains(t) {
+			name := window.Name
+			if name == "" {
+				name = "recurring window"
+			}
+			return true, name
+		}
+	}
+
+	for _, r := range c.Ranges {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			name := r.Name
+			if name == "" {
+				name = "range"
+			}
+			return true, name
+		}
+	}
+
+	return false, ""
+}
+
+// contains 窗口是否包含时间 t
+func (w ExclusionWindow) contains(t time.Time) bool {
+	if w.Start == "" {
+		return w.onDay(t.Weekday())
+	}
+
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	if start < end {
+		return w.onDay(t.Weekday()) && minutes >= start && minutes < end
+	}
+
+	// 跨越午夜的窗口：星期几按窗口开始的那天计算
+	if minutes >= start {
+		return w.onDay(t.Weekday())
+	}
+	return minutes < end && w.onDay((t.Weekday()+6)%7)
+}
+
+// onDay 窗口是否在星期 day 生效
+func (w ExclusionWindow) onDay(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock 解析 HH:MM，返回当天的分钟数
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Settings 全局设置
+type Settings struct {
+	MaxConcurrentTasks  int  `json:"maxConcurrentTasks"`  // 最大并发任务数，0 表示不限制
+	LogsRetentionDays   int  `json:"logsRetentionDays"`   // 日志保留天数，超过的日志由调度器定期删除
+	EnableNotifications bool `json:"enableNotifications"` // 是否启用通知
+	DefaultTimeout      int  `json:"defaultTimeout"`      // 默认超时时间（秒），0 表示不限制
+
+	EnableWebhooks bool   `json:"enableWebhooks"` // 是否启用 Webhook 触发
+	WebhookAddr    string `json:"webhookAddr"`    // Webhook 监听地址，如 127.0.0.1:17380
+}
+
+// DefaultSettings 默认设置
+func DefaultSettings() *Settings {
+	return &Settings{
+		MaxConcurrentTasks:  5,
+		LogsRetentionDays:   30,
+		EnableNotifications: true,
+		DefaultTimeout:      300,
+		WebhookAddr:         "127.0.0.1:17380",
+	}
+}
+
+// TimeoutFor 计算执行超时时间，优先级：任务 > 脚本 > 全局默认值
+// task 可以为 nil（如直接运行脚本），返回 0 表示不限制
+func (s *Settings) TimeoutFor(task *Task, script *Script) time.Duration {
+	seconds := s.DefaultTimeout
+	if script != nil && script.Timeout > 0 {
+		seconds = script.Timeout
+	}
+	if task != nil && task.Timeout > 0 {
+		seconds = task.Timeout
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// SchedulerState 调度器运行状态，重启后保留
+type SchedulerState struct {
+	Paused      bool       `json:"paused"`      // 是否暂停定时执行
+	PausedAt    *time.Time `json:"pausedAt"`    // 暂停时间
+	PausedUntil *time.Time `json:"pausedUntil"` // 自动恢复时间，为空表示需要手动恢复
+	ResumedAt   *time.Time `json:"resumedAt"`   // 最近一次恢复时间，暂停期间错过的执行不补跑
+}
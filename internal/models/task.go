@@ -13,6 +13,7 @@ type Script struct {
 	ScriptPath  string     `json:"scriptPath"` // 脚本文件路径
 	ScriptCode  string     `json:"scriptCode"` // 内联脚本代码
 	Tags        []string   `json:"tags"`       // 标签
+	Timeout     int        `json:"timeout"`    // 超时时间（秒），0 表示使用全局默认值
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
 	LastRunAt   *time.Time `json:"lastRunAt"`
@@ -35,6 +36,16 @@ const (
 	ScriptTypeShell  ScriptType = "shell"
 )
 
+// TriggerType 任务的触发方式
+type TriggerType string
+
+const (
+	TriggerTypeSchedule  TriggerType = "schedule"  // 按调度类型定时触发（空值等同于此）
+	TriggerTypeFileWatch TriggerType = "fileWatch" // 监视的文件或目录发生变化时触发
+	TriggerTypeStartup   TriggerType = "startup"   // 应用启动时触发
+	TriggerTypeResume    TriggerType = "resume"    // 系统从休眠唤醒时触发
+)
+
 // ScheduleType 调度类型
 type ScheduleType string
 
@@ -43,31 +54,212 @@ const (
 	ScheduleTypeWeekly  ScheduleType = "weekly"  // 每周
 	ScheduleTypeMonthly ScheduleType = "monthly" // 每月
 	ScheduleTypeCustom  ScheduleType = "custom"  // 自定义cron
+
+	ScheduleTypeInterval       ScheduleType = "interval"       // 每隔 N 分钟
+	ScheduleTypeLastDayOfMonth ScheduleType = "lastDayOfMonth" // 每月最后一天
+	ScheduleTypeNthWeekday     ScheduleType = "nthWeekday"     // 每月第 N 个星期几
+	ScheduleTypeOnce           ScheduleType = "once"           // 在指定时间执行一次
 )
 
 // Task 定时任务
 type Task struct {
-	ID           string       `json:"id"`
-	Name         string       `json:"name"`
-	ScriptID     string       `json:"scriptId"`     // 关联的脚本ID
-	ScheduleType ScheduleType `json:"scheduleType"` // 调度类型
-	Cron         string       `json:"cron"`         // cron 表达式
-	TimeConfig   TimeConfig   `json:"timeConfig"`   // 时间配置（用于daily/weekly/monthly）
-	Status       TaskStatus   `json:"status"`
-	Description  string       `json:"description"`
-	CreatedAt    time.Time    `json:"createdAt"`
-	UpdatedAt    time.Time    `json:"updatedAt"`
-	LastRunAt    *time.Time   `json:"lastRunAt"`
-	NextRunAt    *time.Time   `json:"nextRunAt"`
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	ScriptID      string        `json:"scriptId"`     // 关联的脚本ID
+	TriggerType   TriggerType   `json:"triggerType"`  // 触发方式
+	TriggerDelay  int           `json:"triggerDelay"` // 启动或唤醒后延迟执行的时间（秒），等待网络等就绪
+	ScheduleType  ScheduleType  `json:"scheduleType"` // 调度类型
+	Cron          string        `json:"cron"`         // cron 表达式
+	Timezone      string        `json:"timezone"`     // 调度时区（IANA 名称，如 Asia/Shanghai），为空使用本地时区
+	TimeConfig    TimeConfig    `json:"timeConfig"`   // 时间配置（用于 custom 以外的调度类型）
+	RunAt         *time.Time    `json:"runAt"`        // 一次性任务的执行时间
+	FileWatch     FileWatch     `json:"fileWatch"`    // 文件监视触发配置
+	Calendars     []string      `json:"calendars"`    // 排除日历ID，落在排除范围内的定时触发会被跳过
+	CompletedAt   *time.Time    `json:"completedAt"`  // 一次性任务的完成时间
+	StartAt       *time.Time    `json:"startAt"`      // 生效时间，之前不触发
+	EndAt         *time.Time    `json:"endAt"`        // 失效时间，之后不再触发并自动停用
+	MaxRuns       int           `json:"maxRuns"`      // 最多定时执行次数，0 表示不限制
+	RunCount      int           `json:"runCount"`     // 已定时执行的次数
+	Status        TaskStatus    `json:"status"`
+	NotifyOn      NotifyPolicy  `json:"notifyOn"`      // 通知策略
+	OverlapPolicy OverlapPolicy `json:"overlapPolicy"` // 重叠执行策略
+	Timeout       int           `json:"timeout"`       // 超时时间（秒），0 表示使用脚本或全局设置
+	Jitter        int           `json:"jitter"`        // 随机延迟窗口（秒），每次定时触发延迟 [0, Jitter) 内的随机时间
+	Retry         RetryPolicy   `json:"retry"`         // 失败重试策略
+	OnSuccess     []string      `json:"onSuccess"`     // 成功后触发的下游任务ID
+	OnFailure     []string      `json:"onFailure"`     // 失败后触发的下游任务ID
+	OnComplete    []string      `json:"onComplete"`    // 结束后（无论成功失败）触发的下游任务ID
+	MisfirePolicy MisfirePolicy `json:"misfirePolicy"` // 错过执行（应用关闭或休眠）时的补跑策略
+	MisfireLimit  int           `json:"misfireLimit"`  // 补跑全部时的最大次数，0 表示使用默认值
+	Watchdog      WatchdogRule  `json:"watchdog"`      // 看门狗：错过执行或运行过久时告警
+	Webhook       WebhookConfig `json:"webhook"`       // Webhook 触发配置
+	Conditions    []Condition   `json:"conditions"`    // 前置条件，全部成立时才执行，否则记录跳过
+	Params        []TaskParam   `json:"params"`        // 参数定义，执行时传给脚本，手动执行可覆盖
+	ParamMode     ParamMode     `json:"paramMode"`     // 参数传递方式，为空表示环境变量
+	Workflow      *Workflow     `json:"workflow"`      // 工作流配置，有步骤时按步骤执行，不使用 ScriptID
+	Description   string        `json:"description"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+	LastRunAt     *time.Time    `json:"lastRunAt"`
+	NextRunAt     *time.Time    `json:"nextRunAt"`
+}
+
+// Expired 任务是否已过失效时间或达到最多执行次数，返回原因
+func (t *Task) Expired(now time.Time) (bool, string) {
+	if t.EndAt != nil && !now.Before(*t.EndAt) {
+		return true, "end time reached"
+	}
+	if t.MaxRuns > 0 && t.RunCount >= t.MaxRuns {
+		return true, "max runs reached"
+	}
+	return false, ""
+}
+
+// IsScheduled 任务是否按调度定时触发
+func (t *Task) IsScheduled() bool {
+	return t.TriggerType == "" || t.TriggerType == TriggerTypeSchedule
+}
+
+// IsWorkflow 任务是否为工作流
+func (t *Task) IsWorkflow() bool {
+	return t.Workflow != nil && len(t.Workflow.Steps) > 0
+}
+
+// FileWatch 文件监视触发配置
+type FileWatch struct {
+	Paths     []string `json:"paths"`     // 监视的文件或目录
+	Patterns  []string `json:"patterns"`  // 文件名通配符（如 *.csv），为空表示不过滤
+	Recursive bool     `json:"recursive"` // 是否监视子目录
+	Events    []string `json:"events"`    // 触发的事件：create/write/remove/rename，为空表示 create 和 write
+	Debounce  int      `json:"debounce"`  // 防抖时间（毫秒），同一文件在该时间内的连续变化只触发一次，0 表示使用默认值
+}
+
+// WatchdogRule 看门狗规则
+type WatchdogRule struct {
+	GracePeriod      int `json:"gracePeriod"`      // 宽限期（秒），超过下次运行时间该时长仍未执行时告警，0 表示不检查
+	ExpectedDuration int `json:"expectedDuration"` // 预期执行时长（秒），运行超过该时长时告警，0 表示不检查
+}
+
+// WebhookConfig Webhook 触发配置
+type WebhookConfig struct {
+	Enabled bool   `json:"enabled"` // 是否允许通过 Webhook 触发
+	Token   string `json:"token"`   // 触发 URL 中的密钥
+}
+
+// ConditionType 前置条件类型
+type ConditionType string
+
+const (
+	ConditionScript         ConditionType = "script"         // 检查脚本退出码为 0
+	ConditionURL            ConditionType = "url"            // URL 可以访问（状态码小于 400）
+	ConditionFileExists     ConditionType = "fileExists"     // 文件存在（支持通配符）
+	ConditionPreviousFailed ConditionType = "previousFailed" // 上一次执行失败
+)
+
+// Condition 执行前检查的条件
+type Condition struct {
+	Type     ConditionType `json:"type"`
+	ScriptID string        `json:"scriptId"` // script：检查脚本ID
+	URL      string        `json:"url"`      // url：检查的地址
+	Path     string        `json:"path"`     // fileExists：文件路径
+	Negate   bool          `json:"negate"`   // 条件取反，如文件不存在时执行
+}
+
+// Workflow 由多个脚本步骤组成的工作流，步骤按依赖关系执行，无依赖关系的分支并行
+type Workflow struct {
+	Steps     []WorkflowStep `json:"steps"`
+	OnFailure StepFailure    `json:"onFailure"` // 步骤失败时的处理方式，为空表示停止
+	CleanupID string         `json:"cleanupId"` // 清理脚本ID，处理方式为 cleanup 时在失败后执行
+}
+
+// WorkflowStep 工作流步骤
+type WorkflowStep struct {
+	ID        string   `json:"id"` // 步骤标识，工作流内唯一
+	Name      string   `json:"name"`
+	ScriptID  string   `json:"scriptId"`
+	DependsOn []string `json:"dependsOn"` // 依赖的步骤，全部成功后才执行
+	Timeout   int      `json:"timeout"`   // 超时时间（秒），0 表示使用脚本或全局设置
+}
+
+// StepFailure 工作流步骤失败时的处理方式
+type StepFailure string
+
+const (
+	StepFailureStop     StepFailure = "stop"     // 不再启动新的步骤，等待运行中的步骤结束
+	StepFailureContinue StepFailure = "continue" // 继续执行不依赖失败步骤的分支
+	StepFailureCleanup  StepFailure = "cleanup"  // 同 stop，所有步骤结束后执行清理脚本
+)
+
+// TaskParam 任务参数
+type TaskParam struct {
+	Name        string `json:"name"`        // 参数名，只能包含字母、数字、下划线和连字符
+	Default     string `json:"default"`     // 默认值
+	Description string `json:"description"` // 说明
+}
+
+// ParamMode 参数传递方式
+type ParamMode string
+
+const (
+	ParamModeEnv  ParamMode = "env"  // 环境变量 TEMPO_PARAM_<NAME>
+	ParamModeArgs ParamMode = "args" // 命令行参数 --name=value
+	ParamModeBoth ParamMode = "both" // 同时使用两种方式
+)
+
+// NotifyPolicy 任务通知策略
+type NotifyPolicy string
+
+const (
+	NotifyAlways    NotifyPolicy = "always"  // 每次执行都通知
+	NotifyOnFailure NotifyPolicy = "failure" // 仅失败时通知（空值等同于此）
+	NotifyOnSuccess NotifyPolicy = "success" // 仅成功时通知
+	NotifyNever     NotifyPolicy = "never"   // 不通知
+)
+
+// OverlapPolicy 上一次执行尚未结束时的处理策略
+type OverlapPolicy string
+
+const (
+	OverlapAllow OverlapPolicy = "allow" // 允许并发执行（空值等同于此）
+	OverlapSkip  OverlapPolicy = "skip"  // 正在运行时跳过本次
+	OverlapQueue OverlapPolicy = "queue" // 正在运行时排队，最多排队一次
+)
+
+// MisfirePolicy 错过执行的补跑策略
+type MisfirePolicy string
+
+const (
+	MisfireIgnore MisfirePolicy = "ignore" // 忽略错过的执行（空值等同于此）
+	MisfireOnce   MisfirePolicy = "once"   // 补跑一次
+	MisfireAll    MisfirePolicy = "all"    // 补跑全部错过的执行（有上限）
+)
+
+// BackoffType 重试间隔策略
+type BackoffType string
+
+const (
+	BackoffFixed       BackoffType = "fixed"       // 固定间隔（空值等同于此）
+	BackoffExponential BackoffType = "exponential" // 指数退避
+)
+
+// RetryPolicy 失败重试策略
+type RetryPolicy struct {
+	MaxAttempts      int         `json:"maxAttempts"`      // 最大尝试次数（含首次），<= 1 表示不重试
+	Backoff          BackoffType `json:"backoff"`          // 重试间隔策略
+	Delay            int         `json:"delay"`            // 首次重试间隔（秒）
+	MaxDelay         int         `json:"maxDelay"`         // 指数退避的最大间隔（秒），0 表示不限制
+	RetryOnExitCodes []int       `json:"retryOnExitCodes"` // 仅这些退出码才重试，为空表示任意失败都重试
 }
 
 // TimeConfig 时间配置
 type TimeConfig struct {
 	Hour     int   `json:"hour"`     // 小时 (0-23)
 	Minute   int   `json:"minute"`   // 分钟 (0-59)
-	Weekday  int   `json:"weekday"`  // 星期几 (0-6, 0=周日) for weekly
+	Weekday  int   `json:"weekday"`  // 星期几 (0-6, 0=周日) for weekly/nthWeekday
 	Monthday int   `json:"monthday"` // 每月第几天 (1-31) for monthly
 	Weekdays []int `json:"weekdays"` // 星期几数组 for weekly multiple days
+	Interval int   `json:"interval"` // 间隔分钟数 for interval
+	Nth      int   `json:"nth"`      // 第几个 (1-5，-1 表示最后一个) for nthWeekday
 }
 
 // TaskLog 任务执行日志
@@ -81,8 +273,64 @@ type TaskLog struct {
 	Output    string    `json:"output"`
 	Error     string    `json:"error"`
 	Success   bool      `json:"success"`
+	Status    LogStatus `json:"status"`
+	ExitCode  int       `json:"exitCode"` // 进程退出码，-1 表示未正常退出
+
+	Attempt     int    `json:"attempt"`     // 第几次尝试，从 1 开始
+	ParentRunID string `json:"parentRunId"` // 重试时指向首次执行的日志ID
+
+	Trigger     TriggerSource `json:"trigger"`     // 触发来源
+	TriggeredBy string        `json:"triggeredBy"` // 由上游任务触发时为上游执行的日志ID
+	TriggerInfo string        `json:"triggerInfo"` // 触发详情，如文件监视触发时变化的文件路径
+	ScheduledAt *time.Time    `json:"scheduledAt"` // 计划触发时间（补跑时为错过的触发时间，有随机延迟时为延迟后的时间）
+	JitterDelay int           `json:"jitterDelay"` // 本次触发的随机延迟（秒）
+
+	Params map[string]string `json:"params"` // 本次执行的实际参数（默认值合并覆盖值后）
+	Steps  []StepLog         `json:"steps"`  // 工作流各步骤的执行日志，按定义顺序排列
+}
+
+// StepLog 工作流步骤的执行日志
+type StepLog struct {
+	Step      string    `json:"step"` // 步骤标识，清理脚本为 cleanup
+	Name      string    `json:"name"`
+	RunID     string    `json:"runId"` // 步骤的执行ID，可用于取消或查看实时输出
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Duration  int64     `json:"duration"` // 毫秒
+	Output    string    `json:"output"`
+	Error     string    `json:"error"`
+	Status    LogStatus `json:"status"`
+	ExitCode  int       `json:"exitCode"`
 }
 
+// TriggerSource 执行的触发来源
+type TriggerSource string
+
+const (
+	TriggerSchedule TriggerSource = "schedule" // 定时调度
+	TriggerManual   TriggerSource = "manual"   // 手动执行
+	TriggerChain    TriggerSource = "chain"    // 上游任务触发
+	TriggerCatchUp  TriggerSource = "catchup"  // 补跑错过的执行
+
+	TriggerFileWatch TriggerSource = "fileWatch" // 文件变化
+	TriggerWebhook   TriggerSource = "webhook"   // Webhook 请求
+	TriggerStartup   TriggerSource = "startup"   // 应用启动
+	TriggerResume    TriggerSource = "resume"    // 系统唤醒
+)
+
+// LogStatus 执行结果状态
+type LogStatus string
+
+const (
+	LogStatusSuccess   LogStatus = "success"   // 成功
+	LogStatusFailed    LogStatus = "failed"    // 失败
+	LogStatusSkipped   LogStatus = "skipped"   // 跳过（未执行）
+	LogStatusCancelled LogStatus = "cancelled" // 被用户取消
+	LogStatusTimeout   LogStatus = "timeout"   // 执行超时
+	LogStatusMissed    LogStatus = "missed"    // 看门狗告警：宽限期内没有按计划执行
+	LogStatusOverdue   LogStatus = "overdue"   // 看门狗告警：执行时间超过预期时长
+)
+
 // NotifierConfig 通知配置
 type NotifierConfig struct {
 	ID        string         `json:"id"`
@@ -0,0 +1,444 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"tempo/internal/models"
+	"time"
+	"unicode/utf8"
+)
+
+// SMTP 安全模式
+const (
+	smtpSecurityNone     = "none"     // 明文连接
+	smtpSecurityStartTLS = "starttls" // 明文连接后升级 TLS（通常为 587 端口）
+	smtpSecurityTLS      = "tls"      // 隐式 TLS（通常为 465 端口）
+)
+
+// emailOutputLimit 邮件正文中输出内容的最大长度
+const emailOutputLimit = 20000
+
+// smtpConfig SMTP 发信配置
+type smtpConfig struct {
+	host       string
+	port       int
+	username   string
+	password   string
+	from       string
+	to         []string
+	security   string
+	skipVerify bool
+}
+
+// sendEmail 发送邮件通知
+func (n *Notifier) sendEmail(config *models.NotifierConfig, taskLog *models.TaskLog) error {
+	cfg, err := parseSMTPConfig(config.Config)
+	if err != nil {
+		return err
+	}
+
+	msg, err := buildEmailMessage(cfg, taskLog)
+	if err != nil {
+		return err
+	}
+
+	return sendSMTPMail(cfg, msg)
+}
+
+// parseSMTPConfig 解析 SMTP 配置
+// 支持的配置项：host、port、username、password、from、to（逗号/分号分隔或数组）、
+// security（none / starttls / tls）、skipVerify
+func parseSMTPConfig(raw map[string]any) (*smtpConfig, error) {
+	cfg := &smtpConfig{
+		host:       configString(raw, "host"),
+		username:   configString(raw, "username"),
+		password:   configString(raw, "password"),
+		from:       configString(raw, "from"),
+		to:         configStrings(raw, "to"),
+		security:   strings.ToLower(configString(raw, "security")),
+		skipVerify: configBool(raw, "skipVerify"),
+	}
+
+	if cfg.host == "" {
+		return nil, fmt.Errorf("smtp host not configured")
+	}
+	if len(cfg.to) == 0 {
+		return nil, fmt.Errorf("email recipients not configured")
+	}
+	if cfg.from == "" {
+		cfg.from = cfg.username
+	}
+	if cfg.from == "" {
+		return nil, fmt.Errorf("email sender not configured")
+	}
+
+	port, err := configInt(raw, "port")
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp port: %w", err)
+	}
+	cfg.port = port
+
+	// 未指定安全模式时根据端口推断
+	if cfg.security == "" {
+		switch cfg.port {
+		case 465:
+			cfg.security = smtpSecurityTLS
+		case 587:
+			cfg.security = smtpSecurityStartTLS
+		default:
+			cfg.security = smtpSecurityNone
+		}
+	}
+
+	if cfg.port == 0 {
+		switch cfg.security {
+		case smtpSecurityTLS:
+			cfg.port = 465
+		case smtpSecurityStartTLS:
+			cfg.port = 587
+		default:
+			cfg.port = 25
+		}
+	}
+
+	switch cfg.security {
+	case smtpSecurityNone, smtpSecurityStartTLS, smtpSecurityTLS:
+	default:
+		return nil, fmt.Errorf("unsupported smtp security mode: %s", cfg.security)
+	}
+
+	return cfg, nil
+}
+
+// sendSMTPMail 通过 SMTP 发送邮件
+func sendSMTPMail(cfg *smtpConfig, msg []byte) error {
+	addr := net.JoinHostPort(cfg.host, strconv.Itoa(cfg.port))
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.host,
+		InsecureSkipVerify: cfg.skipVerify,
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if cfg.security == smtpSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect smtp server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, cfg.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if cfg.security == smtpSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if cfg.username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server does not support authentication")
+		}
+		// PlainAuth 只允许在 TLS 连接或本机地址上发送密码
+		if err := client.Auth(smtp.PlainAuth("", cfg.username, cfg.password, cfg.host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(cfg.from)); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range cfg.to {
+		if err := client.Rcpt(envelopeAddress(rcpt)); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finish email body: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// envelopeAddress 提取信封地址，兼容 "Name <user@example.com>" 格式
+func envelopeAddress(addr string) string {
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		return parsed.Address
+	}
+	return addr
+}
+
+// headerAddress 格式化邮件头中的地址，非 ASCII 的显示名按 RFC 2047 编码，无法解析时原样返回
+func headerAddress(addr string) string {
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil {
+		return addr
+	}
+	if parsed.Name == "" {
+		return parsed.Address
+	}
+	return parsed.String()
+}
+
+// buildEmailMessage 构建包含纯文本和 HTML 两种正文的 MIME 邮件
+func buildEmailMessage(cfg *smtpConfig, taskLog *models.TaskLog) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	plain := buildEmailText(taskLog)
+	html, err := buildEmailHTML(taskLog)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", plain},
+		{"text/html; charset=UTF-8", html},
+	}
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		pw, err := mw.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email part: %w", err)
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to encode email part: %w", err)
+		}
+		qw.Close()
+	}
+	mw.Close()
+
+	to := make([]string, len(cfg.to))
+	for i, addr := range cfg.to {
+		to[i] = headerAddress(addr)
+	}
+
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", headerAddress(cfg.from)},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("UTF-8", buildEmailSubject(taskLog))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", buildMessageID(cfg.from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// buildEmailSubject 构建邮件主题
+func buildEmailSubject(taskLog *models.TaskLog) string {
+	_, status := statusText(taskLog)
+	return fmt.Sprintf("[Tempo] %s - %s", taskLog.TaskName, status)
+}
+
+// buildEmailText 构建纯文本正文
+func buildEmailText(taskLog *models.TaskLog) string {
+	var sb strings.Builder
+
+	if content := extractNotifyContent(taskLog); content != "" {
+		sb.WriteString(content)
+		sb.WriteString("\n\n----------------\n\n")
+	}
+
+	icon, status := statusText(taskLog)
+	fmt.Fprintf(&sb, "%s %s %s\n", icon, status, taskLog.TaskName)
+	fmt.Fprintf(&sb, "开始时间: %s\n", taskLog.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "结束时间: %s\n", taskLog.EndTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "执行时长: %dms\n", taskLog.Duration)
+
+	if taskLog.Error != "" {
+		fmt.Fprintf(&sb, "\n错误:\n%s\n", taskLog.Error)
+	}
+	if output := truncateOutput(taskLog.Output); output != "" {
+		fmt.Fprintf(&sb, "\n输出:\n%s\n", output)
+	}
+
+	return sb.String()
+}
+
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; color: #111827;">
+  <h2 style="margin: 0 0 12px;">{{.Icon}} {{.TaskName}}</h2>
+  <table style="border-collapse: collapse; font-size: 14px;">
+    <tr><td style="padding: 4px 12px 4px 0; color: #6b7280;">状态</td><td>{{.Status}}</td></tr>
+    <tr><td style="padding: 4px 12px 4px 0; color: #6b7280;">开始时间</td><td>{{.StartTime}}</td></tr>
+    <tr><td style="padding: 4px 12px 4px 0; color: #6b7280;">结束时间</td><td>{{.EndTime}}</td></tr>
+    <tr><td style="padding: 4px 12px 4px 0; color: #6b7280;">执行时长</td><td>{{.Duration}}ms</td></tr>
+  </table>
+  {{if .Notify}}
+  <h3 style="margin: 16px 0 8px;">通知内容</h3>
+  <pre style="background: #f3f4f6; padding: 12px; border-radius: 6px; white-space: pre-wrap;">{{.Notify}}</pre>
+  {{end}}
+  {{if .Error}}
+  <h3 style="margin: 16px 0 8px; color: #b91c1c;">错误</h3>
+  <pre style="background: #fef2f2; padding: 12px; border-radius: 6px; white-space: pre-wrap;">{{.Error}}</pre>
+  {{end}}
+  {{if .Output}}
+  <h3 style="margin: 16px 0 8px;">输出</h3>
+  <pre style="background: #f3f4f6; padding: 12px; border-radius: 6px; white-space: pre-wrap;">{{.Output}}</pre>
+  {{end}}
+</body>
+</html>
+`))
+
+// buildEmailHTML 构建 HTML 正文
+func buildEmailHTML(taskLog *models.TaskLog) (string, error) {
+	icon, status := statusText(taskLog)
+	data := map[string]any{
+		"TaskName":  taskLog.TaskName,
+		"Icon":      icon,
+		"Status":    status,
+		"StartTime": taskLog.StartTime.Format("2006-01-02 15:04:05"),
+		"EndTime":   taskLog.EndTime.Format("2006-01-02 15:04:05"),
+		"Duration":  taskLog.Duration,
+		"Notify":    extractNotifyContent(taskLog),
+		"Error":     taskLog.Error,
+		"Output":    truncateOutput(taskLog.Output),
+	}
+
+	var buf bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email html: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// truncateOutput 截断过长的输出，在字符边界处截断以免切开多字节字符
+func truncateOutput(output string) string {
+	if len(output) <= emailOutputLimit {
+		return output
+	}
+	cut := emailOutputLimit
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut] + "\n..."
+}
+
+// buildMessageID 生成邮件 Message-ID
+func buildMessageID(from string) string {
+	domain := "tempo.local"
+	addr := envelopeAddress(from)
+	if at := strings.LastIndex(addr, "@"); at >= 0 && at < len(addr)-1 {
+		domain = addr[at+1:]
+	}
+
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%s.%d@%s>", hex.EncodeToString(b), time.Now().UnixNano(), domain)
+}
+
+// configString 读取字符串配置
+func configString(raw map[string]any, key string) string {
+	switch v := raw[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// configInt 读取整数配置（兼容前端提交的字符串）
+func configInt(raw map[string]any, key string) (int, error) {
+	switch v := raw[key].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int(v), nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(strings.TrimSpace(v))
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+}
+
+// configBool 读取布尔配置
+func configBool(raw map[string]any, key string) bool {
+	switch v := raw[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// configStrings 读取列表配置，支持数组或逗号/分号分隔的字符串
+func configStrings(raw map[string]any, key string) []string {
+	var items []string
+	switch v := raw[key].(type) {
+	case string:
+		items = strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || r == ';' || r == '\n'
+		})
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,300 @@
+package notifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"tempo/internal/models"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// smtpSession 模拟 SMTP 服务器收到的一次会话
+type smtpSession struct {
+	tls  bool
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// startFakeSMTP 在本机端口启动只接受一个连接的 SMTP 服务器，tlsConfig 不为空时支持 STARTTLS
+func startFakeSMTP(t *testing.T, tlsConfig *tls.Config) (int, <-chan smtpSession) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		sessions <- serveSMTP(conn, tlsConfig)
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, sessions
+}
+
+// serveSMTP 处理一次 SMTP 会话，连接关闭或收到 QUIT 时返回
+func serveSMTP(conn net.Conn, tlsConfig *tls.Config) smtpSession {
+	var session smtpSession
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return session
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			lines := []string{"250-fake"}
+			if tlsConfig != nil && !session.tls {
+				lines = append(lines, "250-STARTTLS")
+			}
+			lines = append(lines, "250 AUTH PLAIN")
+			for _, l := range lines {
+				tp.PrintfLine("%s", l)
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return session
+			}
+			tp = textproto.NewConn(tlsConn)
+			session.tls = true
+		case "AUTH":
+			session.auth = arg
+			tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			session.from = arg
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			session.rcpt = append(session.rcpt, arg)
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return session
+			}
+			session.data = strings.Join(lines, "\n")
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return session
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// selfSignedTLS 生成本机地址使用的自签名证书
+func selfSignedTLS(t *testing.T) *tls.Config {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+func testTaskLog() *models.TaskLog {
+	now := time.Now()
+	return &models.TaskLog{
+		ID:        "run-1",
+		TaskID:    "task-1",
+		TaskName:  "nightly-backup",
+		StartTime: now.Add(-time.Second),
+		EndTime:   now,
+		Duration:  1000,
+		Output:    "done",
+		Success:   true,
+		Status:    models.LogStatusSuccess,
+	}
+}
+
+func sendTestEmail(t *testing.T, config map[string]any, sessions <-chan smtpSession) (smtpSession, error) {
+	t.Helper()
+
+	err := New().sendEmail(&models.NotifierConfig{Type: models.NotifierTypeEmail, Config: config}, testTaskLog())
+	select {
+	case session := <-sessions:
+		return session, err
+	case <-time.After(5 * time.Second):
+		t.Fatal("smtp session did not finish")
+		return smtpSession{}, err
+	}
+}
+
+func TestSendEmailPlain(t *testing.T) {
+	port, sessions := startFakeSMTP(t, nil)
+
+	session, err := sendTestEmail(t, map[string]any{
+		"host":     "127.0.0.1",
+		"port":     float64(port),
+		"from":     "Tempo <tempo@example.com>",
+		"to":       "ops@example.com, Bob <bob@example.com>; dev@example.com",
+		"security": "none",
+	}, sessions)
+	if err != nil {
+		t.Fatalf("sendEmail: %v", err)
+	}
+
+	if session.tls {
+		t.Error("plain session should not use TLS")
+	}
+	if session.auth != "" {
+		t.Errorf("unexpected AUTH %q without username", session.auth)
+	}
+	if session.from != "FROM:<tempo@example.com>" {
+		t.Errorf("MAIL %q, want envelope address of sender", session.from)
+	}
+	want := []string{"TO:<ops@example.com>", "TO:<bob@example.com>", "TO:<dev@example.com>"}
+	if strings.Join(session.rcpt, " ") != strings.Join(want, " ") {
+		t.Errorf("RCPT %v, want %v", session.rcpt, want)
+	}
+	if !strings.Contains(session.data, `From: "Tempo" <tempo@example.com>`) {
+		t.Errorf("message missing From header:\n%s", session.data)
+	}
+	if !strings.Contains(session.data, `To: ops@example.com, "Bob" <bob@example.com>, dev@example.com`) {
+		t.Errorf("message missing To header:\n%s", session.data)
+	}
+	if !strings.Contains(session.data, "nightly-backup") {
+		t.Errorf("message missing task name:\n%s", session.data)
+	}
+}
+
+func TestSendEmailStartTLS(t *testing.T) {
+	port, sessions := startFakeSMTP(t, selfSignedTLS(t))
+
+	session, err := sendTestEmail(t, map[string]any{
+		"host":       "127.0.0.1",
+		"port":       float64(port),
+		"username":   "tempo@example.com",
+		"password":   "secret",
+		"to":         []any{"ops@example.com", "dev@example.com"},
+		"security":   "starttls",
+		"skipVerify": true,
+	}, sessions)
+	if err != nil {
+		t.Fatalf("sendEmail: %v", err)
+	}
+
+	if !session.tls {
+		t.Fatal("session was not upgraded with STARTTLS")
+	}
+	mechanism, initial, _ := strings.Cut(session.auth, " ")
+	credentials, _ := base64.StdEncoding.DecodeString(initial)
+	if mechanism != "PLAIN" || string(credentials) != "\x00tempo@example.com\x00secret" {
+		t.Errorf("AUTH %q, want PLAIN credentials", session.auth)
+	}
+	if session.from != "FROM:<tempo@example.com>" {
+		t.Errorf("MAIL %q, want username as sender", session.from)
+	}
+	if len(session.rcpt) != 2 {
+		t.Errorf("RCPT %v, want 2 recipients", session.rcpt)
+	}
+	if session.data == "" {
+		t.Error("message body was not sent")
+	}
+}
+
+func TestSendEmailStartTLSUnsupported(t *testing.T) {
+	port, sessions := startFakeSMTP(t, nil)
+
+	session, err := sendTestEmail(t, map[string]any{
+		"host":     "127.0.0.1",
+		"port":     float64(port),
+		"from":     "tempo@example.com",
+		"to":       "ops@example.com",
+		"security": "starttls",
+	}, sessions)
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("sendEmail error %v, want STARTTLS not supported", err)
+	}
+	if session.data != "" {
+		t.Error("message should not be sent over plain connection")
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int // 截断后保留的字节数
+	}{
+		{"short", "hello", 5},
+		{"ascii at limit", strings.Repeat("a", emailOutputLimit), emailOutputLimit},
+		{"ascii over limit", strings.Repeat("a", emailOutputLimit+10), emailOutputLimit},
+		// "中" 占 3 字节，第 6667 个字符跨过限制，整体退回到前一个字符
+		{"multibyte boundary", strings.Repeat("中", emailOutputLimit/3+5), emailOutputLimit / 3 * 3},
+		{"multibyte offset", "a" + strings.Repeat("中", emailOutputLimit/3+5), 1 + (emailOutputLimit-1)/3*3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateOutput(tt.output)
+			kept := strings.TrimSuffix(got, "\n...")
+			if len(kept) != tt.want {
+				t.Errorf("kept %d bytes, want %d", len(kept), tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Error("truncated output is not valid UTF-8")
+			}
+			if len(tt.output) > emailOutputLimit && !strings.HasSuffix(got, "\n...") {
+				t.Error("truncated output should end with ellipsis")
+			}
+		})
+	}
+}
+
+func TestHeaderAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"tempo@example.com", "tempo@example.com"},
+		{"Tempo <tempo@example.com>", `"Tempo" <tempo@example.com>`},
+		{"定时任务 <tempo@example.com>", "=?utf-8?q?=E5=AE=9A=E6=97=B6=E4=BB=BB=E5=8A=A1?= <tempo@example.com>"},
+		{`"Ops, Night" <ops@example.com>`, `"Ops, Night" <ops@example.com>`},
+		{"not an address", "not an address"},
+	}
+
+	for _, tt := range tests {
+		if got := headerAddress(tt.addr); got != tt.want {
+			t.Errorf("headerAddress(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"tempo/internal/models"
 	"time"
 )
 
 // Notifier 通知器
 type Notifier struct {
+	mu      sync.RWMutex
 	configs map[string]*models.NotifierConfig
 }
 
@@ -30,16 +32,23 @@ func New() *Notifier {
 
 // SetConfigs 设置通知配置
 func (n *Notifier) SetConfigs(configs []*models.NotifierConfig) {
-	n.configs = make(map[string]*models.NotifierConfig)
+	enabled := make(map[string]*models.NotifierConfig)
 	for _, config := range configs {
 		if config.Enabled {
-			n.configs[config.ID] = config
+			enabled[config.ID] = config
 		}
 	}
+
+	n.mu.Lock()
+	n.configs = enabled
+	n.mu.Unlock()
 }
 
 // Notify 发送通知
 func (n *Notifier) Notify(taskLog *models.TaskLog) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
 	for _, config := range n.configs {
 		if !config.Enabled {
 			continue
@@ -56,6 +65,8 @@ func (n *Notifier) Notify(taskLog *models.TaskLog) {
 // send 发送通知
 func (n *Notifier) send(config *models.NotifierConfig, taskLog *models.TaskLog) error {
 	switch config.Type {
+	case models.NotifierTypeEmail:
+		return n.sendEmail(config, taskLog)
 	case models.NotifierTypeDingTalk:
 		return n.sendDingTalk(config, taskLog)
 	case models.NotifierTypeWeChat:
@@ -248,14 +259,25 @@ func extractNotifyContent(taskLog *models.TaskLog) string {
 	return strings.Join(notifyLines, "\n")
 }
 
+// statusText 返回执行结果的图标和文字，看门狗告警单独显示
+func statusText(taskLog *models.TaskLog) (string, string) {
+	switch taskLog.Status {
+	case models.LogStatusMissed:
+		return "⚠️", "错过执行"
+	case models.LogStatusOverdue:
+		return "⚠️", "运行超时"
+	}
+	if taskLog.Success {
+		return "✅", "成功"
+	}
+	return "❌", "失败"
+}
+
 // buildDefaultSummary 构建默认摘要（当没有 [NOTIFY] 内容时）
 func buildDefaultSummary(taskLog *models.TaskLog) string {
-	status := "✅ 成功"
-	if !taskLog.Success {
-		status = "❌ 失败"
-	}
+	icon, status := statusText(taskLog)
 
-	summary := fmt.Sprintf("%s %s\n", status, taskLog.TaskName)
+	summary := fmt.Sprintf("%s %s %s\n", icon, status, taskLog.TaskName)
 	summary += fmt.Sprintf("执行时长: %dms\n", taskLog.Duration)
 
 	if taskLog.Error != "" {
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"fmt"
+	"tempo/internal/models"
+	"time"
+)
+
+// maxBlackoutLookahead 计算下次运行时间时最多跳过的被排除触发次数
+const maxBlackoutLookahead = 1000
+
+// blackout 判断任务在 at 时刻的触发是否落在所挂日历的排除范围内，返回命中的原因
+func (s *Scheduler) blackout(task *models.Task, at time.Time) (bool, string) {
+	local := at.In(taskLocation(task))
+	for _, id := range task.Calendars {
+		calendar, err := s.storage.GetCalendar(id)
+		if err != nil {
+			continue
+		}
+		if excluded, reason := calendar.Excludes(local); excluded {
+			return true, fmt.Sprintf("calendar %s: %s", calendar.Name, reason)
+		}
+	}
+	return false, ""
+}
+
+// nextAllowed 返回 next 及之后第一个不在排除范围内的触发时间，找不到时返回零值
+func (s *Scheduler) nextAllowed(task *models.Task, next time.Time, schedule func(time.Time) time.Time) time.Time {
+	if len(task.Calendars) == 0 {
+		return next
+	}
+	for i := 0; i < maxBlackoutLookahead && !next.IsZero(); i++ {
+		if excluded, _ := s.blackout(task, next); !excluded {
+			return next
+		}
+		next = schedule(next)
+	}
+	return time.Time{}
+}
+
+// RefreshNextRuns 重新计算所有已调度任务的下次运行时间（如排除日历变更后）
+func (s *Scheduler) RefreshNextRuns() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for taskID := range s.jobs {
+		s.refreshTaskNextRun(taskID)
+	}
+}
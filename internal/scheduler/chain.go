@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"tempo/internal/models"
+)
+
+// triggerDownstream 根据执行结果触发下游任务
+func (s *Scheduler) triggerDownstream(taskID string, taskLog *models.TaskLog) {
+	task, err := s.storage.GetTask(taskID)
+	if err != nil {
+		return
+	}
+
+	var downstream []string
+	switch taskLog.Status {
+	case models.LogStatusSuccess:
+		downstream = append(downstream, task.OnSuccess...)
+	case models.LogStatusFailed, models.LogStatusTimeout:
+		downstream = append(downstream, task.OnFailure...)
+	}
+	downstream = append(downstream, task.OnComplete...)
+
+	seen := make(map[string]bool)
+	for _, id := range downstream {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		log.Printf("Task %s triggers downstream task %s", task.Name, id)
+		go s.executeTask(runRequest{
+			taskID:      id,
+			trigger:     models.TriggerChain,
+			triggeredBy: taskLog.ID,
+		})
+	}
+}
+
+// ValidateChain 校验任务的下游引用：目标任务必须存在，且不能形成环
+// tasks 为当前所有任务，task 为待保存的任务（会替换 tasks 中的同ID任务）
+func ValidateChain(tasks []*models.Task, task *models.Task) error {
+	graph := make(map[string][]string)
+	names := make(map[string]string)
+	for _, t := range tasks {
+		graph[t.ID] = downstreamOf(t)
+		names[t.ID] = t.Name
+	}
+	graph[task.ID] = downstreamOf(task)
+	names[task.ID] = task.Name
+
+	for _, id := range graph[task.ID] {
+		if _, ok := graph[id]; !ok {
+			return fmt.Errorf("downstream task not found: %s", id)
+		}
+	}
+
+	// 从待保存的任务出发做深度优先搜索，回到自身即存在环
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		state[id] = visiting
+		path = append(path, names[id])
+		for _, next := range graph[id] {
+			switch state[next] {
+			case visiting:
+				return fmt.Errorf("task chain contains a cycle: %v -> %s", path, names[next])
+			case unvisited:
+				if err := visit(next); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	return visit(task.ID)
+}
+
+// downstreamOf 返回任务的所有下游任务ID
+func downstreamOf(task *models.Task) []string {
+	ids := make([]string, 0, len(task.OnSuccess)+len(task.OnFailure)+len(task.OnComplete))
+	ids = append(ids, task.OnSuccess...)
+	ids = append(ids, task.OnFailure...)
+	ids = append(ids, task.OnComplete...)
+	return ids
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"tempo/internal/models"
+)
+
+func TestValidateChain(t *testing.T) {
+	// a -> b -> c，d 独立
+	existing := func() []*models.Task {
+		return []*models.Task{
+			{ID: "a", Name: "A", OnSuccess: []string{"b"}},
+			{ID: "b", Name: "B", OnFailure: []string{"c"}},
+			{ID: "c", Name: "C"},
+			{ID: "d", Name: "D"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		task    *models.Task
+		wantErr string
+	}{
+		{name: "no downstream", task: &models.Task{ID: "c", Name: "C"}},
+		{name: "new task into chain", task: &models.Task{ID: "e", Name: "E", OnComplete: []string{"a"}}},
+		{name: "diamond", task: &models.Task{ID: "d", Name: "D", OnSuccess: []string{"b", "c"}}},
+		{name: "missing downstream", task: &models.Task{ID: "c", Name: "C", OnSuccess: []string{"x"}}, wantErr: "downstream task not found: x"},
+		{name: "self reference", task: &models.Task{ID: "d", Name: "D", OnComplete: []string{"d"}}, wantErr: "[D] -> D"},
+		{name: "cycle through success", task: &models.Task{ID: "c", Name: "C", OnSuccess: []string{"a"}}, wantErr: "[C A B] -> C"},
+		{name: "cycle through failure", task: &models.Task{ID: "b", Name: "B", OnFailure: []string{"a"}}, wantErr: "[B A] -> B"},
+		{name: "update breaks cycle", task: &models.Task{ID: "b", Name: "B", OnSuccess: []string{"d"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChain(existing(), tt.task)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateChain: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateChain error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
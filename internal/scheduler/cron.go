@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 预览次数限制
+const (
+	defaultPreviewCount = 5
+	maxPreviewCount     = 50
+)
+
+// cronFieldNames 6 位表达式各字段名称
+var cronFieldNames = []string{"second", "minute", "hour", "dom", "month", "dow"}
+
+// CronError cron 表达式解析错误，Position/Length 指向表达式中出错的字段
+type CronError struct {
+	Message  string `json:"message"`
+	Field    string `json:"field"`
+	Position int    `json:"position"`
+	Length   int    `json:"length"`
+}
+
+// Error 实现 error 接口
+func (e *CronError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("invalid cron expression: %s field at position %d: %s", e.Field, e.Position, e.Message)
+	}
+	return fmt.Sprintf("invalid cron expression: %s", e.Message)
+}
+
+// CronValidation cron 表达式校验结果
+type CronValidation struct {
+	Valid    bool        `json:"valid"`
+	Error    *CronError  `json:"error,omitempty"`
+	NextRuns []time.Time `json:"nextRuns"`
+}
+
+// cronField 表达式中的一个字段及其位置
+type cronField struct {
+	text     string
+	position int
+}
+
+// ParseCron 使用调度器的解析器校验表达式（秒字段可选，支持 @daily、@every 5m 等描述符）
+func ParseCron(expr, timezone string) error {
+	if _, err := parseSchedule(expr, timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateCron 校验表达式并返回之后 count 次的触发时间
+func ValidateCron(expr, timezone string, count int) *CronValidation {
+	result := &CronValidation{NextRuns: []time.Time{}}
+
+	schedule, err := parseSchedule(expr, timezone)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Valid = true
+
+	if count <= 0 {
+		count = defaultPreviewCount
+	}
+	if count > maxPreviewCount {
+		count = maxPreviewCount
+	}
+
+	next := time.Now()
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		result.NextRuns = append(result.NextRuns, next)
+	}
+	return result
+}
+
+// parseSchedule 解析表达式，timezone 非空且表达式没有时区前缀时按该时区计算
+func parseSchedule(expr, timezone string) (cron.Schedule, *CronError) {
+	spec := expr
+	if timezone != "" && !hasTZPrefix(expr) {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, &CronError{Message: fmt.Sprintf("invalid timezone %q", timezone), Field: "timezone"}
+		}
+		spec = "CRON_TZ=" + timezone + " " + expr
+	}
+
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, locateCronError(expr, err)
+	}
+	return schedule, nil
+}
+
+// hasTZPrefix 表达式是否自带时区前缀
+func hasTZPrefix(expr string) bool {
+	expr = strings.TrimSpace(expr)
+	return strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=")
+}
+
+// locateCronError 将解析错误定位到出错的字段
+func locateCronError(expr string, err error) *CronError {
+	fields := splitCronFields(expr)
+	if len(fields) == 0 {
+		return &CronError{Message: "empty expression"}
+	}
+
+	// 时区前缀
+	if hasTZPrefix(fields[0].text) {
+		tz := fields[0].text[strings.Index(fields[0].text, "=")+1:]
+		if _, tzErr := time.LoadLocation(tz); tzErr != nil {
+			return &CronError{
+				Message:  fmt.Sprintf("invalid timezone %q", tz),
+				Field:    "timezone",
+				Position: fields[0].position,
+				Length:   len(fields[0].text),
+			}
+		}
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return &CronError{Message: "empty expression"}
+		}
+	}
+
+	// 描述符
+	if strings.HasPrefix(fields[0].text, "@") {
+		start := fields[0].position
+		last := fields[len(fields)-1]
+		return &CronError{
+			Message:  err.Error(),
+			Field:    "descriptor",
+			Position: start,
+			Length:   last.position + len(last.text) - start,
+		}
+	}
+
+	if len(fields) != 5 && len(fields) != 6 {
+		start := fields[0].position
+		last := fields[len(fields)-1]
+		return &CronError{
+			Message:  fmt.Sprintf("expected 5 or 6 fields, found %d", len(fields)),
+			Position: start,
+			Length:   last.position + len(last.text) - start,
+		}
+	}
+
+	names := cronFieldNames
+	if len(fields) == 5 {
+		names = cronFieldNames[1:]
+	}
+
+	// 逐个字段单独解析，其余字段用 * 代替，找出第一个无效字段
+	for i, field := range fields {
+		parts := make([]string, len(fields))
+		for j := range parts {
+			parts[j] = "*"
+		}
+		parts[i] = field.text
+		if _, fieldErr := cronParser.Parse(strings.Join(parts, " ")); fieldErr != nil {
+			return &CronError{
+				Message:  fieldErr.Error(),
+				Field:    names[i],
+				Position: field.position,
+				Length:   len(field.text),
+			}
+		}
+	}
+
+	return &CronError{Message: err.Error()}
+}
+
+// splitCronFields 按空白拆分表达式并记录每个字段的起始位置
+func splitCronFields(expr string) []cronField {
+	var fields []cronField
+	start := -1
+	for i, r := range expr {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields = append(fields, cronField{text: expr[start:i], position: start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, cronField{text: expr[start:], position: start})
+	}
+	return fields
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import "testing"
+
+func TestLocateCronError(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		field    string
+		position int
+		length   int
+	}{
+		{name: "empty", expr: "   "},
+		{name: "minute of five fields", expr: "61 * * * *", field: "minute", position: 0, length: 2},
+		{name: "hour of five fields", expr: "0 25 * * *", field: "hour", position: 2, length: 2},
+		{name: "dow after extra spaces", expr: "0  0 *  * MON-XYZ", field: "dow", position: 10, length: 7},
+		{name: "second of six fields", expr: "60 0 0 * * *", field: "second", position: 0, length: 2},
+		{name: "month of six fields", expr: "0 0 0 1 13 *", field: "month", position: 8, length: 2},
+		{name: "dom extension", expr: "0 0 32 * *", field: "dom", position: 4, length: 2},
+		{name: "nth weekday", expr: "0 0 * * 1#6", field: "dow", position: 8, length: 3},
+		{name: "field count", expr: "0 0 * *", position: 0, length: 7},
+		{name: "field count after timezone", expr: "CRON_TZ=UTC 0 0 * * * * *", position: 12, length: 13},
+		{name: "invalid timezone", expr: "CRON_TZ=Mars/Base 0 0 * * *", field: "timezone", position: 0, length: 17},
+		{name: "field after timezone", expr: "TZ=UTC 0 24 * * *", field: "hour", position: 9, length: 2},
+		{name: "descriptor", expr: "@weekly2", field: "descriptor", position: 0, length: 8},
+		{name: "descriptor after timezone", expr: "CRON_TZ=UTC @every 5x", field: "descriptor", position: 12, length: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cronErr := parseSchedule(tt.expr, "")
+			if cronErr == nil {
+				t.Fatalf("parseSchedule(%q) succeeded, want error", tt.expr)
+			}
+			if cronErr.Field != tt.field || cronErr.Position != tt.position || cronErr.Length != tt.length {
+				t.Errorf("error at %s [%d, +%d), want %s [%d, +%d): %s",
+					cronErr.Field, cronErr.Position, cronErr.Length, tt.field, tt.position, tt.length, cronErr.Message)
+			}
+		})
+	}
+}
+
+func TestParseScheduleTimezone(t *testing.T) {
+	if _, err := parseSchedule("0 0 * * *", "Mars/Base"); err == nil || err.Field != "timezone" {
+		t.Errorf("invalid task timezone: error = %v, want timezone error", err)
+	}
+	// 表达式自带时区前缀时不使用任务时区
+	if _, err := parseSchedule("CRON_TZ=UTC 0 0 * * *", "Mars/Base"); err != nil {
+		t.Errorf("prefixed expression: %v", err)
+	}
+}
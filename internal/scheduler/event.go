@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"log"
+	"tempo/internal/models"
+	"time"
+)
+
+// isEventTriggered 是否为应用启动或系统唤醒时触发的任务
+func isEventTriggered(task *models.Task) bool {
+	return task.TriggerType == models.TriggerTypeStartup || task.TriggerType == models.TriggerTypeResume
+}
+
+// fireEvent 应用启动或系统唤醒后，按各任务的延迟执行对应触发方式的启用任务
+// 延迟期间再次发生同类事件时不重复触发
+func (s *Scheduler) fireEvent(triggerType models.TriggerType, source models.TriggerSource) {
+	for _, task := range s.storage.GetAllTasks() {
+		if task.Status != models.TaskStatusActive || task.TriggerType != triggerType {
+			continue
+		}
+
+		s.eventMu.Lock()
+		pending := s.pendingEvents[task.ID]
+		s.pendingEvents[task.ID] = true
+		s.eventMu.Unlock()
+		if pending {
+			continue
+		}
+
+		delay := time.Duration(task.TriggerDelay) * time.Second
+		log.Printf("Task %s will run in %s (%s)", task.Name, delay, source)
+		taskID := task.ID
+		time.AfterFunc(delay, func() {
+			s.eventMu.Lock()
+			delete(s.pendingEvents, taskID)
+			s.eventMu.Unlock()
+			s.runEvent(taskID, triggerType, source)
+		})
+	}
+}
+
+// runEvent 延迟结束后执行任务，期间任务被停用、修改触发方式或调度器暂停时放弃
+func (s *Scheduler) runEvent(taskID string, triggerType models.TriggerType, source models.TriggerSource) {
+	if !s.IsRunning() {
+		return
+	}
+	task, err := s.storage.GetTask(taskID)
+	if err != nil || task.Status != models.TaskStatusActive || task.TriggerType != triggerType {
+		return
+	}
+	if paused, _ := s.IsPaused(); paused {
+		log.Printf("Scheduler paused, skipping %s run of task %s", source, task.Name)
+		return
+	}
+
+	s.executeTask(runRequest{
+		taskID:  taskID,
+		trigger: source,
+		env:     map[string]string{"TEMPO_TRIGGER": string(source)},
+	})
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxSearchYears 扩展表达式向后查找触发时间的最大年数
+const maxSearchYears = 5
+
+// extendedParser 在标准解析器之上支持日期字段的扩展写法：
+//   - 日字段 L：每月最后一天
+//   - 周字段 w#n：每月第 n 个星期 w（n 为 1-5）
+//   - 周字段 wL：每月最后一个星期 w
+//
+// 扩展字段之外的部分仍由标准解析器处理，两者同时满足时触发。
+type extendedParser struct {
+	base cron.Parser
+}
+
+// Parse 实现 cron.ScheduleParser
+func (p extendedParser) Parse(spec string) (cron.Schedule, error) {
+	fields := strings.Fields(spec)
+	prefix := ""
+	if len(fields) > 0 && hasTZPrefix(fields[0]) {
+		prefix = fields[0] + " "
+		fields = fields[1:]
+	}
+	if len(fields) != 5 && len(fields) != 6 {
+		return p.base.Parse(spec)
+	}
+
+	domIndex, dowIndex := len(fields)-3, len(fields)-1
+	var matchers []dayMatcher
+
+	if fields[domIndex] == "L" {
+		matchers = append(matchers, lastDayOfMonth)
+		fields[domIndex] = "*"
+	}
+
+	if matcher, ok, err := parseWeekdayExtension(fields[dowIndex]); err != nil {
+		return nil, err
+	} else if ok {
+		matchers = append(matchers, matcher)
+		fields[dowIndex] = "*"
+	}
+
+	schedule, err := p.base.Parse(prefix + strings.Join(fields, " "))
+	if err != nil || len(matchers) == 0 {
+		return schedule, err
+	}
+
+	base, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return schedule, nil
+	}
+	return &dayFilterSchedule{base: base, matchers: matchers}, nil
+}
+
+// dayMatcher 判断某天是否满足扩展条件
+type dayMatcher func(t time.Time) bool
+
+// lastDayOfMonth 是否为当月最后一天
+func lastDayOfMonth(t time.Time) bool {
+	return t.AddDate(0, 0, 1).Month() != t.Month()
+}
+
+// parseWeekdayExtension 解析周字段的 w#n / wL 写法，不是扩展写法时返回 ok=false
+func parseWeekdayExtension(field string) (dayMatcher, bool, error) {
+	if i := strings.Index(field, "#"); i >= 0 {
+		weekday, err := parseWeekday(field[:i])
+		if err != nil {
+			return nil, false, err
+		}
+		nth, err := strconv.Atoi(field[i+1:])
+		if err != nil || nth < 1 || nth > 5 {
+			return nil, false, fmt.Errorf("invalid occurrence in %q: must be 1-5", field)
+		}
+		return func(t time.Time) bool {
+			return t.Weekday() == weekday && (t.Day()-1)/7+1 == nth
+		}, true, nil
+	}
+
+	if len(field) > 1 && strings.HasSuffix(field, "L") {
+		weekday, err := parseWeekday(strings.TrimSuffix(field, "L"))
+		if err != nil {
+			return nil, false, err
+		}
+		return func(t time.Time) bool {
+			return t.Weekday() == weekday && t.AddDate(0, 0, 7).Month() != t.Month()
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// parseWeekday 解析 0-6 的星期值（7 同样表示周日）
+func parseWeekday(value string) (time.Weekday, error) {
+	weekday, err := strconv.Atoi(value)
+	if err != nil || weekday < 0 || weekday > 7 {
+		return 0, fmt.Errorf("invalid weekday %q: must be 0-7", value)
+	}
+	return time.Weekday(weekday % 7), nil
+}
+
+// dayFilterSchedule 只在满足扩展日期条件的日子触发的调度
+type dayFilterSchedule struct {
+	base     *cron.SpecSchedule
+	matchers []dayMatcher
+}
+
+// Next 实现 cron.Schedule
+func (s *dayFilterSchedule) Next(t time.Time) time.Time {
+	loc := s.base.Location
+	if loc == time.Local {
+		loc = t.Location()
+	}
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	next := s.base.Next(t)
+	for !next.IsZero() && next.Before(limit) {
+		day := next.In(loc)
+		if s.matches(day) {
+			return next
+		}
+		// 跳到下一天的开始继续查找
+		tomorrow := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+		next = s.base.Next(tomorrow.Add(-time.Second))
+	}
+	return time.Time{}
+}
+
+// matches 是否满足全部扩展条件
+func (s *dayFilterSchedule) matches(t time.Time) bool {
+	for _, match := range s.matchers {
+		if !match(t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestExtendedParserNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		from  string
+		wants []string
+	}{
+		{
+			name:  "last day across month ends",
+			spec:  "0 0 L * *",
+			from:  "2024-01-15T00:00:00Z",
+			wants: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z", "2024-04-30T00:00:00Z"},
+		},
+		{
+			name:  "last day of february in common year",
+			spec:  "0 0 L * *",
+			from:  "2023-02-01T00:00:00Z",
+			wants: []string{"2023-02-28T00:00:00Z", "2023-03-31T00:00:00Z"},
+		},
+		{
+			name:  "last day after its run time",
+			spec:  "0 9 L * *",
+			from:  "2024-01-31T10:00:00Z",
+			wants: []string{"2024-02-29T09:00:00Z"},
+		},
+		{
+			name:  "last day across year end",
+			spec:  "0 0 L 2 *",
+			from:  "2023-03-01T00:00:00Z",
+			wants: []string{"2024-02-29T00:00:00Z", "2025-02-28T00:00:00Z"},
+		},
+		{
+			name:  "last day with seconds",
+			spec:  "30 0 0 L * *",
+			from:  "2024-01-15T00:00:00Z",
+			wants: []string{"2024-01-31T00:00:30Z"},
+		},
+		{
+			name:  "last day in cron timezone",
+			spec:  "CRON_TZ=Asia/Shanghai 0 0 L * *",
+			from:  "2024-01-31T00:00:00Z",
+			wants: []string{"2024-02-28T16:00:00Z", "2024-03-30T16:00:00Z"},
+		},
+		{
+			name:  "first monday",
+			spec:  "0 0 * * 1#1",
+			from:  "2024-01-01T00:00:00Z",
+			wants: []string{"2024-02-05T00:00:00Z", "2024-03-04T00:00:00Z"},
+		},
+		{
+			name:  "second sunday written as 7",
+			spec:  "0 0 * * 7#2",
+			from:  "2024-05-01T00:00:00Z",
+			wants: []string{"2024-05-12T00:00:00Z", "2024-06-09T00:00:00Z"},
+		},
+		{
+			name:  "fifth friday skips four-friday months",
+			spec:  "0 0 * * 5#5",
+			from:  "2024-01-01T00:00:00Z",
+			wants: []string{"2024-03-29T00:00:00Z", "2024-05-31T00:00:00Z", "2024-08-30T00:00:00Z"},
+		},
+		{
+			name:  "fifth tuesday across year end",
+			spec:  "0 0 * * 2#5",
+			from:  "2024-11-01T00:00:00Z",
+			wants: []string{"2024-12-31T00:00:00Z", "2025-04-29T00:00:00Z"},
+		},
+		{
+			name:  "last sunday",
+			spec:  "0 0 * * 0L",
+			from:  "2024-01-01T00:00:00Z",
+			wants: []string{"2024-01-28T00:00:00Z", "2024-02-25T00:00:00Z", "2024-03-31T00:00:00Z"},
+		},
+		{
+			name:  "last day that is a friday",
+			spec:  "0 0 L * 5L",
+			from:  "2024-01-01T00:00:00Z",
+			wants: []string{"2024-05-31T00:00:00Z", "2025-01-31T00:00:00Z"},
+		},
+		{
+			name:  "fifth monday of february",
+			spec:  "0 0 * 2 1#5",
+			from:  "2040-03-01T00:00:00Z",
+			wants: []string{"2044-02-29T00:00:00Z"},
+		},
+		{
+			// 下一个 2 月第 5 个周一在 2044 年，超出查找范围
+			name:  "no match within search limit",
+			spec:  "0 0 * 2 1#5",
+			from:  "2024-01-01T00:00:00Z",
+			wants: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := cronParser.Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if _, ok := schedule.(*dayFilterSchedule); !ok {
+				t.Fatalf("Parse(%q) = %T, want *dayFilterSchedule", tt.spec, schedule)
+			}
+
+			next, _ := time.Parse(time.RFC3339, tt.from)
+			for _, want := range tt.wants {
+				next = schedule.Next(next)
+				got := ""
+				if !next.IsZero() {
+					got = next.UTC().Format(time.RFC3339)
+				}
+				if got != want {
+					t.Fatalf("Next = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtendedParserErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 0 * * 1#0",
+		"0 0 * * 1#6",
+		"0 0 * * 8#1",
+		"0 0 * * x#1",
+		"0 0 * * 1#",
+		"0 0 * * 9L",
+		"0 0 L * * * *",
+	} {
+		if _, err := cronParser.Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestExtendedParserStandard(t *testing.T) {
+	for _, spec := range []string{"0 0 1 * *", "0 0 * * 1", "@daily", "0 0 * * MON-FRI"} {
+		schedule, err := cronParser.Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		if _, ok := schedule.(*cron.SpecSchedule); !ok {
+			t.Errorf("Parse(%q) = %T, want *cron.SpecSchedule", spec, schedule)
+		}
+	}
+}
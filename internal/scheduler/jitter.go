@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// jitterSchedule 在原调度的每次触发上叠加 [0, window) 内的随机延迟
+// 延迟由任务ID和原触发时间散列得到，重启后同一次触发的延迟不变，NextRunAt 因此可以提前给出实际开始时间
+type jitterSchedule struct {
+	base   cron.Schedule
+	taskID string
+	window time.Duration
+}
+
+// Next 实现 cron.Schedule
+// 随机延迟可能超过原调度的间隔，延迟后的顺序与原触发不同，因此取晚于 t 的最早计划时间
+func (s jitterSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	// 延迟后晚于 t 的触发可能来自 (t-window, t] 内的原触发；原触发晚于 next 后不会再有更早的计划时间
+	for n := s.base.Next(t.Add(-s.window)); !n.IsZero() && (next.IsZero() || n.Before(next)); n = s.base.Next(n) {
+		if planned := n.Add(s.offset(n)); planned.After(t) && (next.IsZero() || planned.Before(next)) {
+			next = planned
+		}
+	}
+	return next
+}
+
+// offset 原触发时间 n 对应的延迟
+func (s jitterSchedule) offset(n time.Time) time.Duration {
+	seconds := int64(s.window / time.Second)
+	if seconds <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(s.taskID))
+	h.Write([]byte(strconv.FormatInt(n.Unix(), 10)))
+	return time.Duration(h.Sum64()%uint64(seconds)) * time.Second
+}
+
+// delayOf 返回计划开始时间 planned 相对原触发时间的延迟
+func (s jitterSchedule) delayOf(planned time.Time) time.Duration {
+	for n := s.base.Next(planned.Add(-s.window)); !n.IsZero() && !n.After(planned); n = s.base.Next(n) {
+		if n.Add(s.offset(n)).Equal(planned) {
+			return planned.Sub(n)
+		}
+	}
+	return 0
+}
+
+// plannedStart 返回任务本次定时触发的计划开始时间及其中的随机延迟
+func (s *Scheduler) plannedStart(taskID string) (time.Time, time.Duration) {
+	s.mu.RLock()
+	entryID, ok := s.jobs[taskID]
+	s.mu.RUnlock()
+	if !ok {
+		return time.Time{}, 0
+	}
+
+	entry := s.cron.Entry(entryID)
+	schedule, ok := entry.Schedule.(jitterSchedule)
+	if !ok || entry.Prev.IsZero() {
+		return time.Time{}, 0
+	}
+	return entry.Prev, schedule.delayOf(entry.Prev)
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestJitterScheduleNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		window time.Duration
+	}{
+		{name: "window within interval", spec: "0 * * * *", window: 10 * time.Minute},
+		{name: "window equals interval", spec: "*/5 * * * *", window: 5 * time.Minute},
+		{name: "window exceeds interval", spec: "* * * * *", window: 5 * time.Minute},
+		{name: "window far exceeds interval", spec: "*/2 * * * *", window: time.Hour},
+		{name: "no window", spec: "*/15 * * * *", window: 0},
+	}
+
+	from := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	until := from.Add(3 * time.Hour)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := cronParser.Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			s := jitterSchedule{base: base, taskID: "task-1", window: tt.window}
+
+			// 逐个原触发计算计划时间，排序后即为期望的执行顺序
+			var want []time.Time
+			for n := base.Next(from.Add(-tt.window)); !n.After(until); n = base.Next(n) {
+				offset := s.offset(n)
+				if offset < 0 || (tt.window > 0 && offset >= tt.window) || (tt.window == 0 && offset != 0) {
+					t.Fatalf("offset(%v) = %v, want within [0, %v)", n, offset, tt.window)
+				}
+				if offset != s.offset(n) {
+					t.Fatalf("offset(%v) is not stable", n)
+				}
+				if planned := n.Add(offset); planned.After(from) && !planned.After(until) {
+					want = append(want, planned)
+				}
+			}
+			sort.Slice(want, func(i, j int) bool { return want[i].Before(want[j]) })
+
+			// 同一时刻的多次计划只会触发一次
+			var got []time.Time
+			for next := s.Next(from); !next.IsZero() && !next.After(until); next = s.Next(next) {
+				got = append(got, next)
+			}
+			want = dedupTimes(want)
+			if len(got) != len(want) {
+				t.Fatalf("got %d firings, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !got[i].Equal(want[i]) {
+					t.Fatalf("firing %d = %v, want %v", i, got[i], want[i])
+				}
+				if delay := s.delayOf(got[i]); delay < 0 || (tt.window > 0 && delay >= tt.window) {
+					t.Errorf("delayOf(%v) = %v, want within [0, %v)", got[i], delay, tt.window)
+				}
+			}
+		})
+	}
+}
+
+// dedupTimes 去掉已排序时间中的重复项
+func dedupTimes(times []time.Time) []time.Time {
+	var out []time.Time
+	for _, t := range times {
+		if len(out) == 0 || !out[len(out)-1].Equal(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
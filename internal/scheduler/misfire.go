@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"log"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// clockCheckInterval 检测系统休眠/时钟跳变的间隔
+	clockCheckInterval = 30 * time.Second
+	// clockJumpThreshold 墙上时间比单调时间多走超过该值时认为发生了休眠或时钟跳变
+	clockJumpThreshold = time.Minute
+	// defaultMisfireLimit 补跑全部错过的执行时的默认上限
+	defaultMisfireLimit = 10
+)
+
+// watchClock 定期比较墙上时间和单调时间，检测系统休眠唤醒或时钟跳变
+func (s *Scheduler) watchClock(stop <-chan struct{}) {
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			wall := now.Round(0).Sub(last.Round(0))
+			mono := now.Sub(last)
+			last = now
+
+			s.resumeIfDue(time.Now())
+			if paused, _ := s.IsPaused(); !paused && wall-mono > clockJumpThreshold {
+				log.Printf("Detected clock jump of %s (system resumed from sleep?)", wall-mono)
+				s.reloadJobs()
+				s.catchUp(time.Now())
+				s.fireEvent(models.TriggerTypeResume, models.TriggerResume)
+			}
+			s.expireTasks(now)
+		}
+	}
+}
+
+// reloadJobs 重新注册所有 cron 条目，丢弃休眠前计算的过期触发时间
+func (s *Scheduler) reloadJobs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reregisterJobs()
+}
+
+// reregisterJobs 重新注册所有 cron 条目（内部方法，不加锁）
+// 先收集任务ID再逐个重新注册，避免遍历 s.jobs 时增删其中的条目
+func (s *Scheduler) reregisterJobs() {
+	taskIDs := make([]string, 0, len(s.jobs))
+	for taskID := range s.jobs {
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	for _, taskID := range taskIDs {
+		s.removeJob(taskID)
+		task, err := s.storage.GetTask(taskID)
+		if err != nil || task.Status != models.TaskStatusActive {
+			continue
+		}
+		if err := s.addJob(task); err != nil {
+			log.Printf("Failed to re-add job %s: %v", task.Name, err)
+		}
+	}
+}
+
+// catchUp 按各任务的错过执行策略补跑 now 之前错过的执行
+func (s *Scheduler) catchUp(now time.Time) {
+	for _, task := range s.storage.GetAllTasks() {
+		if task.Status == models.TaskStatusActive && isOnce(task) {
+			s.catchUpOnce(task, now)
+			continue
+		}
+		if task.Status != models.TaskStatusActive || task.MisfirePolicy == "" || task.MisfirePolicy == models.MisfireIgnore {
+			continue
+		}
+
+		missed := s.missedRuns(task, now)
+		if len(missed) == 0 {
+			continue
+		}
+
+		log.Printf("Task %s missed %d run(s), catching up (policy: %s)", task.Name, len(missed), task.MisfirePolicy)
+		go func(taskID string, missed []time.Time) {
+			// 顺序补跑，避免同一任务的多次补跑同时执行
+			for _, scheduledAt := range missed {
+				s.executeTask(runRequest{
+					taskID:      taskID,
+					trigger:     models.TriggerCatchUp,
+					scheduledAt: scheduledAt,
+				})
+			}
+		}(task.ID, missed)
+	}
+}
+
+// missedRuns 计算任务在上次运行之后、now 之前错过的触发时间（按策略截取）
+func (s *Scheduler) missedRuns(task *models.Task, now time.Time) []time.Time {
+	since := task.UpdatedAt
+	if task.LastRunAt != nil {
+		since = *task.LastRunAt
+	}
+	// 暂停期间错过的执行不补跑
+	if resumed := s.storage.GetSchedulerState().ResumedAt; resumed != nil && resumed.After(since) {
+		since = *resumed
+	}
+	if since.IsZero() || !since.Before(now) {
+		return nil
+	}
+
+	schedule, err := taskSchedule(task)
+	if err != nil {
+		return nil
+	}
+
+	limit := 1
+	if task.MisfirePolicy == models.MisfireAll {
+		limit = task.MisfireLimit
+		if limit <= 0 {
+			limit = defaultMisfireLimit
+		}
+	}
+
+	return missedTimes(schedule, since, now, limit)
+}
+
+// missedTimes 返回 (since, now] 区间内的触发时间
+// 超过 limit 时保留最近的 limit 次
+func missedTimes(schedule cron.Schedule, since, now time.Time, limit int) []time.Time {
+	var missed []time.Time
+	for t := schedule.Next(since); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		missed = append(missed, t)
+		if len(missed) > limit {
+			missed = missed[1:]
+		}
+	}
+	return missed
+}
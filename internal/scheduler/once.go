@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"tempo/internal/models"
+	"time"
+)
+
+// onceSchedule 只在指定时间触发一次的调度
+type onceSchedule struct {
+	at time.Time
+}
+
+// Next 实现 cron.Schedule，触发时间已过时返回零值
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if s.at.After(t) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// isOnce 是否为一次性任务
+func isOnce(task *models.Task) bool {
+	return task.IsScheduled() && task.ScheduleType == models.ScheduleTypeOnce
+}
+
+// validateOnce 校验一次性任务的执行时间，已完成的任务不再要求执行时间在未来
+func validateOnce(task *models.Task, now time.Time) error {
+	if task.RunAt == nil {
+		return fmt.Errorf("run time is required for one-time tasks")
+	}
+	if task.CompletedAt == nil && !task.RunAt.After(now) {
+		return fmt.Errorf("run time %s is in the past", task.RunAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// completeOnce 一次性任务触发后将其停用并记录完成时间
+func (s *Scheduler) completeOnce(taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.storage.GetTask(taskID)
+	if err != nil {
+		log.Printf("Failed to get task %s: %v", taskID, err)
+		return
+	}
+
+	now := time.Now()
+	task := *current
+	task.Status = models.TaskStatusInactive
+	task.CompletedAt = &now
+	task.NextRunAt = nil
+	s.removeJob(taskID)
+
+	if err := s.storage.SaveTask(&task); err != nil {
+		log.Printf("Failed to mark one-time task %s as completed: %v", task.Name, err)
+		return
+	}
+	log.Printf("One-time task completed: %s", task.Name)
+}
+
+// catchUpOnce 处理启动或唤醒时已过执行时间的一次性任务
+// 已开始执行但未记录完成的直接标记完成；策略为 ignore 或未设置时记录跳过并停用，否则补跑一次
+func (s *Scheduler) catchUpOnce(task *models.Task, now time.Time) {
+	if task.CompletedAt != nil || task.RunAt == nil || task.RunAt.After(now) {
+		return
+	}
+
+	if task.LastRunAt != nil && !task.LastRunAt.Before(*task.RunAt) {
+		s.completeOnce(task.ID)
+		return
+	}
+
+	if task.MisfirePolicy == "" || task.MisfirePolicy == models.MisfireIgnore {
+		log.Printf("One-time task %s missed its run time, skipped", task.Name)
+		s.recordSkip(task, "skipped: missed one-time run at "+task.RunAt.Format(time.RFC3339))
+		s.completeOnce(task.ID)
+		return
+	}
+
+	log.Printf("One-time task %s missed its run time, running now", task.Name)
+	go s.executeTask(runRequest{
+		taskID:      task.ID,
+		trigger:     models.TriggerCatchUp,
+		scheduledAt: *task.RunAt,
+	})
+}
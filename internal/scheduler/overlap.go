@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"tempo/internal/models"
+)
+
+// taskState 任务运行状态
+type taskState struct {
+	running int         // 正在运行的实例数
+	queued  *runRequest // 排队等待的执行，最多一次
+}
+
+// startDecision 触发时的处理结果
+type startDecision int
+
+const (
+	startNow     startDecision = iota // 立即执行
+	startQueued                       // 已排队，等待当前执行结束
+	startSkipped                      // 跳过本次
+)
+
+// tryStart 根据任务的重叠策略决定本次触发如何处理
+// 排队时保存本次请求，当前执行结束后由 finish 取出
+func (s *Scheduler) tryStart(task *models.Task, req runRequest) startDecision {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	state, ok := s.states[task.ID]
+	if !ok {
+		state = &taskState{}
+		s.states[task.ID] = state
+	}
+
+	if state.running > 0 {
+		switch task.OverlapPolicy {
+		case models.OverlapSkip:
+			return startSkipped
+		case models.OverlapQueue:
+			if state.queued != nil {
+				return startSkipped
+			}
+			state.queued = &req
+			return startQueued
+		}
+	}
+
+	state.running++
+	return startNow
+}
+
+// finish 标记一次执行结束，如有排队的执行则返回该请求并由调用方继续执行
+func (s *Scheduler) finish(taskID string) (runRequest, bool) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	state, ok := s.states[taskID]
+	if !ok {
+		return runRequest{}, false
+	}
+
+	state.running--
+	if state.running == 0 && state.queued != nil {
+		req := *state.queued
+		state.queued = nil
+		state.running++
+		return req, true
+	}
+
+	if state.running == 0 {
+		delete(s.states, taskID)
+	}
+	return runRequest{}, false
+}
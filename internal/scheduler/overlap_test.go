@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"testing"
+
+	"tempo/internal/models"
+)
+
+func TestOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy models.OverlapPolicy
+		want   []startDecision // 依次触发 3 次，第一次执行尚未结束
+	}{
+		{"skip", models.OverlapSkip, []startDecision{startNow, startSkipped, startSkipped}},
+		{"queue keeps one", models.OverlapQueue, []startDecision{startNow, startQueued, startSkipped}},
+		{"allow", models.OverlapAllow, []startDecision{startNow, startNow, startNow}},
+		{"empty allows", "", []startDecision{startNow, startNow, startNow}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scheduler{states: make(map[string]*taskState)}
+			task := &models.Task{ID: "task", OverlapPolicy: tt.policy}
+			for i, want := range tt.want {
+				if got := s.tryStart(task, runRequest{taskID: task.ID}); got != want {
+					t.Fatalf("trigger %d: tryStart = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOverlapFinish(t *testing.T) {
+	s := &Scheduler{states: make(map[string]*taskState)}
+	task := &models.Task{ID: "task", OverlapPolicy: models.OverlapQueue}
+
+	s.tryStart(task, runRequest{taskID: task.ID, trigger: models.TriggerSchedule})
+	s.tryStart(task, runRequest{taskID: task.ID, trigger: models.TriggerManual, triggeredBy: "queued"})
+
+	// 第一次结束后取出排队的请求，并计为正在运行
+	req, ok := s.finish(task.ID)
+	if !ok || req.trigger != models.TriggerManual || req.triggeredBy != "queued" {
+		t.Fatalf("finish = %+v, %v, want the queued request", req, ok)
+	}
+	if got := s.tryStart(task, runRequest{taskID: task.ID}); got != startQueued {
+		t.Fatalf("tryStart while queued run executes = %v, want startQueued", got)
+	}
+
+	if _, ok := s.finish(task.ID); !ok {
+		t.Fatal("second finish should return the request queued during the queued run")
+	}
+	if _, ok := s.finish(task.ID); ok {
+		t.Fatal("finish without queued request returned one")
+	}
+	if _, ok := s.states[task.ID]; ok {
+		t.Error("state should be removed once no run is left")
+	}
+	if got := s.tryStart(task, runRequest{taskID: task.ID}); got != startNow {
+		t.Errorf("tryStart after all runs finished = %v, want startNow", got)
+	}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"tempo/internal/models"
+)
+
+// paramNamePattern 参数名格式，需要能同时作为环境变量名的一部分和命令行选项
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// ValidateParams 校验任务的参数定义和传递方式
+func ValidateParams(params []models.TaskParam, mode models.ParamMode) error {
+	switch mode {
+	case "", models.ParamModeEnv, models.ParamModeArgs, models.ParamModeBoth:
+	default:
+		return fmt.Errorf("unsupported parameter mode: %s", mode)
+	}
+
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		if !paramNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		key := paramEnvName(param.Name)
+		if seen[key] {
+			return fmt.Errorf("duplicate parameter %q", param.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// checkOverrides 检查覆盖的参数是否都已在任务中定义
+func checkOverrides(task *models.Task, overrides map[string]string) error {
+	defined := make(map[string]bool, len(task.Params))
+	for _, param := range task.Params {
+		defined[param.Name] = true
+	}
+
+	var unknown []string
+	for name := range overrides {
+		if !defined[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("task %s has no parameter %s", task.Name, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// resolveParams 用覆盖值合并参数默认值，返回本次执行的实际参数，任务没有定义参数时返回 nil
+// 未定义的覆盖值被忽略（任务可能在排队期间被修改）
+func resolveParams(task *models.Task, overrides map[string]string) map[string]string {
+	if len(task.Params) == 0 {
+		return nil
+	}
+
+	params := make(map[string]string, len(task.Params))
+	for _, param := range task.Params {
+		value := param.Default
+		if override, ok := overrides[param.Name]; ok {
+			value = override
+		}
+		params[param.Name] = value
+	}
+	return params
+}
+
+// paramInput 按任务的传递方式将参数转换为环境变量和命令行参数，命令行参数按定义顺序排列
+func paramInput(task *models.Task, params map[string]string) (map[string]string, []string) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	mode := task.ParamMode
+	if mode == "" {
+		mode = models.ParamModeEnv
+	}
+
+	var env map[string]string
+	var args []string
+	for _, param := range task.Params {
+		value := params[param.Name]
+		if mode == models.ParamModeEnv || mode == models.ParamModeBoth {
+			if env == nil {
+				env = make(map[string]string, len(params))
+			}
+			env[paramEnvName(param.Name)] = value
+		}
+		if mode == models.ParamModeArgs || mode == models.ParamModeBoth {
+			args = append(args, "--"+param.Name+"="+value)
+		}
+	}
+	return env, args
+}
+
+// paramEnvName 参数对应的环境变量名，如 batch-size 对应 TEMPO_PARAM_BATCH_SIZE
+func paramEnvName(name string) string {
+	return "TEMPO_PARAM_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"tempo/internal/models"
+	"time"
+)
+
+// Pause 暂停所有定时执行，until 为空时需要手动恢复
+// 暂停不改变任务状态，立即执行及其触发的下游任务照常执行；暂停期间错过的定时执行在恢复后不补跑
+func (s *Scheduler) Pause(until *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if until != nil && !until.After(now) {
+		return fmt.Errorf("pause end time %s is in the past", until.Format(time.RFC3339))
+	}
+
+	state := s.storage.GetSchedulerState()
+	if !state.Paused {
+		state.PausedAt = &now
+	}
+	state.Paused = true
+	state.PausedUntil = until
+	if err := s.storage.SaveSchedulerState(state); err != nil {
+		return fmt.Errorf("failed to save scheduler state: %w", err)
+	}
+
+	if s.running && !s.paused {
+		// 不等待执行中的任务结束
+		s.cron.Stop()
+	}
+	s.paused = true
+	s.pausedUntil = until
+	s.scheduleResume()
+
+	if until != nil {
+		log.Printf("Scheduler paused until %s", until.Format(time.RFC3339))
+	} else {
+		log.Println("Scheduler paused")
+	}
+	return nil
+}
+
+// Resume 恢复定时执行
+func (s *Scheduler) Resume() error {
+	s.mu.Lock()
+	if !s.paused {
+		s.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	state := s.storage.GetSchedulerState()
+	state.Paused = false
+	state.PausedAt = nil
+	state.PausedUntil = nil
+	state.ResumedAt = &now
+	if err := s.storage.SaveSchedulerState(state); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to save scheduler state: %w", err)
+	}
+
+	s.paused = false
+	s.pausedUntil = nil
+	s.scheduleResume()
+	running := s.running
+	if running {
+		// 重新注册条目，下次运行时间从现在开始计算
+		s.reregisterJobs()
+		s.cron.Start()
+	}
+	s.mu.Unlock()
+
+	log.Println("Scheduler resumed")
+	if running {
+		// 暂停期间到期的一次性任务按错过执行策略处理，其他任务不补跑
+		go func() {
+			for _, task := range s.storage.GetAllTasks() {
+				if task.Status == models.TaskStatusActive && isOnce(task) {
+					s.catchUpOnce(task, now)
+				}
+			}
+			s.expireTasks(now)
+		}()
+	}
+	return nil
+}
+
+// IsPaused 检查定时执行是否已暂停，返回自动恢复时间（为空表示需要手动恢复）
+func (s *Scheduler) IsPaused() (bool, *time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paused, s.pausedUntil
+}
+
+// restorePause 启动时恢复持久化的暂停状态，返回是否仍处于暂停中（调用方需持有锁）
+func (s *Scheduler) restorePause(now time.Time) bool {
+	state := s.storage.GetSchedulerState()
+	if !state.Paused {
+		return false
+	}
+
+	if state.PausedUntil != nil && !now.Before(*state.PausedUntil) {
+		state.Paused = false
+		state.PausedAt = nil
+		state.PausedUntil = nil
+		state.ResumedAt = &now
+		if err := s.storage.SaveSchedulerState(state); err != nil {
+			log.Printf("Failed to save scheduler state: %v", err)
+		}
+		log.Println("Scheduler pause expired while the app was closed, resuming")
+		return false
+	}
+
+	s.paused = true
+	s.pausedUntil = state.PausedUntil
+	s.scheduleResume()
+	return true
+}
+
+// scheduleResume 按自动恢复时间设置定时器，调用方需持有锁
+func (s *Scheduler) scheduleResume() {
+	if s.resumeTimer != nil {
+		s.resumeTimer.Stop()
+		s.resumeTimer = nil
+	}
+	if !s.paused || s.pausedUntil == nil {
+		return
+	}
+	s.resumeTimer = time.AfterFunc(time.Until(*s.pausedUntil), func() {
+		s.resumeIfDue(time.Now())
+	})
+}
+
+// resumeIfDue 到达自动恢复时间时恢复定时执行
+// 定时器在系统休眠期间可能延后，时钟检测也会调用它
+func (s *Scheduler) resumeIfDue(now time.Time) {
+	paused, until := s.IsPaused()
+	if !paused || until == nil || now.Before(*until) {
+		return
+	}
+	if err := s.Resume(); err != nil {
+		log.Printf("Failed to resume scheduler: %v", err)
+	}
+}
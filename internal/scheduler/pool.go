@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// RunInfo 排队或运行中的执行
+type RunInfo struct {
+	RunID     string     `json:"runId"`
+	TaskID    string     `json:"taskId"`
+	TaskName  string     `json:"taskName"`
+	QueuedAt  time.Time  `json:"queuedAt"`
+	StartedAt *time.Time `json:"startedAt"`
+}
+
+// waiter 排队中的运行
+type waiter struct {
+	run       RunInfo
+	ready     chan struct{}
+	cancelled bool
+}
+
+// workerPool 限制同时运行脚本数量的执行池
+type workerPool struct {
+	mu      sync.Mutex
+	limit   int
+	active  map[string]RunInfo
+	waiting []*waiter
+}
+
+// newWorkerPool 创建执行池，limit <= 0 表示不限制
+func newWorkerPool(limit int) *workerPool {
+	return &workerPool{
+		limit:  limit,
+		active: make(map[string]RunInfo),
+	}
+}
+
+// acquire 获取执行槽位，没有空闲槽位时按先进先出排队等待
+// 排队期间被取消时返回 false
+func (p *workerPool) acquire(run RunInfo) bool {
+	p.mu.Lock()
+	if len(p.waiting) == 0 && p.hasSlot() {
+		p.start(run)
+		p.mu.Unlock()
+		return true
+	}
+
+	w := &waiter{run: run, ready: make(chan struct{})}
+	p.waiting = append(p.waiting, w)
+	p.mu.Unlock()
+
+	<-w.ready
+	return !w.cancelled
+}
+
+// tryAcquire 有空闲槽位且没有排队的运行时占用槽位，否则立即返回 false
+func (p *workerPool) tryAcquire(run RunInfo) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.waiting) > 0 || !p.hasSlot() {
+		return false
+	}
+	p.start(run)
+	return true
+}
+
+// release 释放执行槽位并唤醒排队中的运行
+func (p *workerPool) release(runID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.active, runID)
+	p.dispatch()
+}
+
+// cancel 取消排队中的运行，运行不在队列中时返回 false
+func (p *workerPool) cancel(runID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, w := range p.waiting {
+		if w.run.RunID == runID {
+			p.waiting = append(p.waiting[:i], p.waiting[i+1:]...)
+			w.cancelled = true
+			close(w.ready)
+			return true
+		}
+	}
+	return false
+}
+
+// setLimit 调整并发上限
+func (p *workerPool) setLimit(limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.limit = limit
+	p.dispatch()
+}
+
+// queued 返回排队中的运行
+func (p *workerPool) queued() []RunInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	runs := make([]RunInfo, 0, len(p.waiting))
+	for _, w := range p.waiting {
+		runs = append(runs, w.run)
+	}
+	return runs
+}
+
+// running 返回运行中的执行
+func (p *workerPool) running() []RunInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	runs := make([]RunInfo, 0, len(p.active))
+	for _, run := range p.active {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// hasSlot 是否有空闲槽位（调用方需持有锁）
+func (p *workerPool) hasSlot() bool {
+	return p.limit <= 0 || len(p.active) < p.limit
+}
+
+// start 占用槽位（调用方需持有锁）
+func (p *workerPool) start(run RunInfo) {
+	now := time.Now()
+	run.StartedAt = &now
+	p.active[run.RunID] = run
+}
+
+// dispatch 将空闲槽位分配给排队的运行（调用方需持有锁）
+func (p *workerPool) dispatch() {
+	for len(p.waiting) > 0 && p.hasSlot() {
+		w := p.waiting[0]
+		p.waiting = p.waiting[1:]
+		p.start(w.run)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"tempo/internal/executor"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// conditionScriptTimeout 检查脚本的超时时间
+	conditionScriptTimeout = time.Minute
+	// conditionURLTimeout URL 检查的超时时间
+	conditionURLTimeout = 10 * time.Second
+)
+
+// ValidateCondition 校验前置条件配置（不检查脚本是否存在）
+func ValidateCondition(cond models.Condition) error {
+	switch cond.Type {
+	case models.ConditionScript:
+		if cond.ScriptID == "" {
+			return fmt.Errorf("check script is required for script conditions")
+		}
+	case models.ConditionURL:
+		if cond.URL == "" {
+			return fmt.Errorf("url is required for url conditions")
+		}
+	case models.ConditionFileExists:
+		if cond.Path == "" {
+			return fmt.Errorf("path is required for fileExists conditions")
+		}
+		if _, err := filepath.Match(cond.Path, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q: %w", cond.Path, err)
+		}
+	case models.ConditionPreviousFailed:
+	default:
+		return fmt.Errorf("unsupported condition type: %s", cond.Type)
+	}
+	return nil
+}
+
+// checkConditions 依次检查任务的前置条件，返回是否全部成立及第一个不成立条件的说明
+func (s *Scheduler) checkConditions(task *models.Task) (bool, string) {
+	for _, cond := range task.Conditions {
+		ok, detail := s.evalCondition(task, cond)
+		if ok == cond.Negate {
+			return false, detail
+		}
+	}
+	return true, ""
+}
+
+// evalCondition 检查单个条件（不考虑取反），返回结果及实际情况的说明
+func (s *Scheduler) evalCondition(task *models.Task, cond models.Condition) (bool, string) {
+	switch cond.Type {
+	case models.ConditionScript:
+		script, err := s.storage.GetScript(cond.ScriptID)
+		if err != nil {
+			return false, fmt.Sprintf("check script %s: %v", cond.ScriptID, err)
+		}
+		// 检查脚本同样占用执行槽位，受最大并发数限制
+		runID := uuid.New().String()
+		if !s.pool.acquire(RunInfo{
+			RunID:    runID,
+			TaskID:   task.ID,
+			TaskName: task.Name + " / check",
+			QueuedAt: time.Now(),
+		}) {
+			return false, fmt.Sprintf("check script %s cancelled", script.Name)
+		}
+		defer s.pool.release(runID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), conditionScriptTimeout)
+		defer cancel()
+		result := s.executor.Execute(ctx, runID, script.ScriptType, script.ScriptPath, script.ScriptCode, executor.RunInput{
+			Env: map[string]string{"TEMPO_TASK_ID": task.ID},
+		})
+		return result.Success, fmt.Sprintf("check script %s exited with %d", script.Name, result.ExitCode)
+
+	case models.ConditionURL:
+		client := &http.Client{Timeout: conditionURLTimeout}
+		resp, err := client.Get(cond.URL)
+		if err != nil {
+			return false, fmt.Sprintf("%s unreachable: %v", cond.URL, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode < 400, fmt.Sprintf("%s returned %d", cond.URL, resp.StatusCode)
+
+	case models.ConditionFileExists:
+		if matches, err := filepath.Glob(cond.Path); err != nil || len(matches) == 0 {
+			if _, err := os.Stat(cond.Path); err != nil {
+				return false, fmt.Sprintf("file %s does not exist", cond.Path)
+			}
+		}
+		return true, fmt.Sprintf("file %s exists", cond.Path)
+
+	case models.ConditionPreviousFailed:
+		last := s.lastRun(task.ID)
+		if last == nil {
+			return false, "no previous run"
+		}
+		if last.Success {
+			return false, "previous run succeeded"
+		}
+		return true, "previous run failed"
+	}
+	return false, fmt.Sprintf("unsupported condition type %s", cond.Type)
+}
+
+// lastRun 返回任务最近一次实际执行的日志（不含跳过、取消和错过的记录）
+func (s *Scheduler) lastRun(taskID string) *models.TaskLog {
+	var last *models.TaskLog
+	for _, taskLog := range s.storage.GetTaskLogs(taskID, 0) {
+		switch taskLog.Status {
+		case models.LogStatusSkipped, models.LogStatusCancelled, models.LogStatusMissed:
+			continue
+		}
+		if last == nil || taskLog.StartTime.After(last.StartTime) {
+			last = taskLog
+		}
+	}
+	return last
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// logPruneInterval 清理过期日志的间隔
+const logPruneInterval = time.Hour
+
+// pruneLogs 启动时及之后定期删除超过保留天数的日志
+func (s *Scheduler) pruneLogs(stop <-chan struct{}) {
+	s.pruneExpiredLogs(time.Now())
+
+	ticker := time.NewTicker(logPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.pruneExpiredLogs(now)
+		}
+	}
+}
+
+// pruneExpiredLogs 按当前设置的保留天数删除过期日志，保留天数 <= 0 时不删除
+func (s *Scheduler) pruneExpiredLogs(now time.Time) {
+	days := s.storage.GetSettings().LogsRetentionDays
+	if days <= 0 {
+		return
+	}
+
+	removed, err := s.storage.DeleteLogsBefore(now.AddDate(0, 0, -days))
+	if err != nil {
+		log.Printf("Failed to prune logs: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Pruned %d logs older than %d days", removed, days)
+	}
+}
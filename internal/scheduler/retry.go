@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"log"
+	"slices"
+	"tempo/internal/models"
+	"time"
+)
+
+// maxRetryDelay 指数退避在未配置上限时的最大间隔
+const maxRetryDelay = time.Hour
+
+// retryWait 等待重试间隔的执行
+type retryWait struct {
+	run    RunInfo
+	cancel chan struct{}
+}
+
+// nextRetry 根据重试策略判断第 attempt 次执行失败后是否重试，以及重试前等待的时间
+func nextRetry(policy models.RetryPolicy, attempt int, taskLog *models.TaskLog) (time.Duration, bool) {
+	if attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+
+	switch taskLog.Status {
+	case models.LogStatusFailed, models.LogStatusTimeout:
+	default:
+		return 0, false
+	}
+
+	if len(policy.RetryOnExitCodes) > 0 && !slices.Contains(policy.RetryOnExitCodes, taskLog.ExitCode) {
+		return 0, false
+	}
+
+	delay := time.Duration(policy.Delay) * time.Second
+	if policy.Backoff == models.BackoffExponential {
+		limit := maxRetryDelay
+		if policy.MaxDelay > 0 {
+			limit = time.Duration(policy.MaxDelay) * time.Second
+		}
+		for i := 1; i < attempt && delay < limit; i++ {
+			delay *= 2
+		}
+		delay = min(delay, limit)
+	}
+
+	return delay, true
+}
+
+// waitRetry 等待重试间隔，期间以上一次尝试的执行ID出现在排队列表中
+// 调度器停止或该执行被取消时返回 false
+func (s *Scheduler) waitRetry(taskLog *models.TaskLog, delay time.Duration) bool {
+	wait := &retryWait{
+		run: RunInfo{
+			RunID:    taskLog.ID,
+			TaskID:   taskLog.TaskID,
+			TaskName: taskLog.TaskName,
+			QueuedAt: time.Now(),
+		},
+		cancel: make(chan struct{}),
+	}
+	s.retryMu.Lock()
+	s.retryWaits[taskLog.ID] = wait
+	s.retryMu.Unlock()
+	defer func() {
+		s.retryMu.Lock()
+		delete(s.retryWaits, taskLog.ID)
+		s.retryMu.Unlock()
+	}()
+
+	s.mu.RLock()
+	stop := s.stop
+	s.mu.RUnlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		log.Printf("Scheduler stopped, retry of task %s abandoned", taskLog.TaskName)
+		return false
+	case <-wait.cancel:
+		log.Printf("Retry of task %s cancelled", taskLog.TaskName)
+		return false
+	}
+}
+
+// cancelRetry 取消等待中的重试
+func (s *Scheduler) cancelRetry(runID string) bool {
+	s.retryMu.Lock()
+	defer s.retryMu.Unlock()
+
+	wait, ok := s.retryWaits[runID]
+	if !ok {
+		return false
+	}
+	delete(s.retryWaits, runID)
+	close(wait.cancel)
+	return true
+}
+
+// waitingRetries 返回等待重试间隔的执行
+func (s *Scheduler) waitingRetries() []RunInfo {
+	s.retryMu.Lock()
+	defer s.retryMu.Unlock()
+
+	runs := make([]RunInfo, 0, len(s.retryWaits))
+	for _, wait := range s.retryWaits {
+		runs = append(runs, wait.run)
+	}
+	return runs
+}
@@ -4,36 +4,126 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"sync"
 	"tempo/internal/executor"
 	"tempo/internal/models"
 	"tempo/internal/storage"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
+// cronParser cron 表达式解析器（秒字段可选，支持 @daily、@every 等描述符，以及 L、w#n 扩展）
+var cronParser = extendedParser{
+	base: cron.NewParser(
+		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	),
+}
+
+// NotifyFunc 任务执行完成后的通知回调
+type NotifyFunc func(taskLog *models.TaskLog)
+
 // Scheduler 定时调度器
 type Scheduler struct {
 	cron     *cron.Cron
 	storage  *storage.Storage
 	executor *executor.Executor
+	notify   NotifyFunc
+	pool     *workerPool
 	jobs     map[string]cron.EntryID
+	watchers map[string]*fileWatcher
 	mu       sync.RWMutex
 	running  bool
+	stop     chan struct{}
+
+	paused      bool        // 定时执行已暂停
+	pausedUntil *time.Time  // 自动恢复时间
+	resumeTimer *time.Timer // 自动恢复定时器
+
+	states  map[string]*taskState
+	stateMu sync.Mutex
+
+	pendingEvents map[string]bool // 等待延迟结束的启动/唤醒触发
+	eventMu       sync.Mutex
+
+	workflows  map[string]*workflowRun // 运行中的工作流，按执行ID索引
+	workflowMu sync.Mutex
+
+	retryWaits map[string]*retryWait // 等待重试的执行，按上一次尝试的执行ID索引
+	retryMu    sync.Mutex
 }
 
 // New 创建调度器
 func New(storage *storage.Storage, executor *executor.Executor) *Scheduler {
 	return &Scheduler{
-		cron:     cron.New(cron.WithSeconds()),
+		cron:     cron.New(cron.WithParser(cronParser)),
 		storage:  storage,
 		executor: executor,
+		pool:     newWorkerPool(storage.GetSettings().MaxConcurrentTasks),
 		jobs:     make(map[string]cron.EntryID),
+		watchers: make(map[string]*fileWatcher),
 		running:  false,
+		states:   make(map[string]*taskState),
+
+		pendingEvents: make(map[string]bool),
+		workflows:     make(map[string]*workflowRun),
+		retryWaits:    make(map[string]*retryWait),
 	}
 }
 
+// SetNotifyFunc 设置通知回调，所有定时执行和立即执行都会经过它
+func (s *Scheduler) SetNotifyFunc(fn NotifyFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = fn
+}
+
+// SetMaxConcurrent 设置最大并发执行数，<= 0 表示不限制
+func (s *Scheduler) SetMaxConcurrent(limit int) {
+	s.pool.setLimit(limit)
+}
+
+// QueuedRuns 获取等待执行槽位或等待重试的运行
+func (s *Scheduler) QueuedRuns() []RunInfo {
+	return append(s.pool.queued(), s.waitingRetries()...)
+}
+
+// RunningRuns 获取正在执行的运行
+func (s *Scheduler) RunningRuns() []RunInfo {
+	return s.pool.running()
+}
+
+// AcquireSlot 为不经过调度器的执行（如手动运行脚本）获取执行槽位
+// 返回释放函数；排队期间被取消时返回 false
+func (s *Scheduler) AcquireSlot(runID, name string) (func(), bool) {
+	ok := s.pool.acquire(RunInfo{
+		RunID:    runID,
+		TaskName: name,
+		QueuedAt: time.Now(),
+	})
+	return func() { s.pool.release(runID) }, ok
+}
+
+// CancelRun 取消排队中或运行中的执行
+func (s *Scheduler) CancelRun(runID string) error {
+	if s.pool.cancel(runID) {
+		log.Printf("Cancelled queued run: %s", runID)
+		return nil
+	}
+	if s.cancelRetry(runID) {
+		log.Printf("Cancelled pending retry: %s", runID)
+		return nil
+	}
+	if s.cancelWorkflow(runID) {
+		log.Printf("Cancelled workflow run: %s", runID)
+		return nil
+	}
+	return s.executor.Cancel(runID)
+}
+
 // Start 启动调度器
 func (s *Scheduler) Start() error {
 	s.mu.Lock()
@@ -43,6 +133,14 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("scheduler already running")
 	}
 
+	paused := s.restorePause(time.Now())
+
+	// 在重新计算下次运行时间之前，检查应用未运行期间（如崩溃）错过的执行
+	var downtimeAlerts []*models.TaskLog
+	if !paused {
+		downtimeAlerts = s.checkMissed(time.Now(), make(map[string]time.Time))
+	}
+
 	// 加载所有活动任务
 	tasks := s.storage.GetAllTasks()
 	for _, task := range tasks {
@@ -53,26 +151,56 @@ func (s *Scheduler) Start() error {
 		}
 	}
 
-	s.cron.Start()
+	// 暂停中只注册条目，恢复时再启动 cron
+	if !paused {
+		s.cron.Start()
+	}
 	s.running = true
-	log.Println("Scheduler started")
+	s.stop = make(chan struct{})
+	log.Printf("Scheduler started (paused: %v)", paused)
+
+	// 补跑应用关闭期间错过的执行，停用已失效的任务，并监测休眠唤醒
+	go func(now time.Time) {
+		if !paused {
+			for _, taskLog := range downtimeAlerts {
+				s.alert(taskLog)
+			}
+			s.catchUp(now)
+		}
+		s.expireTasks(now)
+		s.fireEvent(models.TriggerTypeStartup, models.TriggerStartup)
+	}(time.Now())
+	go s.watchClock(s.stop)
+	go s.watchdog(s.stop)
+	go s.pruneLogs(s.stop)
 
 	return nil
 }
 
 // Stop 停止调度器
+// 先通知等待重试的执行放弃，再在锁外等待正在执行的任务结束，避免任务结束时获取锁死锁
 func (s *Scheduler) Stop() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.running {
+		s.mu.Unlock()
 		return
 	}
 
-	ctx := s.cron.Stop()
-	<-ctx.Done()
+	if s.resumeTimer != nil {
+		s.resumeTimer.Stop()
+		s.resumeTimer = nil
+	}
 
+	for taskID := range s.watchers {
+		s.removeWatcher(taskID)
+	}
+
+	ctx := s.cron.Stop()
+	close(s.stop)
 	s.running = false
+	s.mu.Unlock()
+
+	<-ctx.Done()
 	log.Println("Scheduler stopped")
 }
 
@@ -82,6 +210,12 @@ func (s *Scheduler) AddTask(task *models.Task) error {
 	defer s.mu.Unlock()
 
 	if task.Status == models.TaskStatusActive {
+		if isOnce(task) && task.RunAt != nil && !task.RunAt.After(time.Now()) {
+			return fmt.Errorf("run time of one-time task %s has passed", task.Name)
+		}
+		if expired, reason := task.Expired(time.Now()); expired {
+			return fmt.Errorf("task %s has expired: %s", task.Name, reason)
+		}
 		return s.addJob(task)
 	}
 
@@ -114,35 +248,162 @@ func (s *Scheduler) UpdateTask(task *models.Task) error {
 
 // addJob 添加任务（内部方法，不加锁）
 func (s *Scheduler) addJob(task *models.Task) error {
+	if isOnce(task) && task.CompletedAt != nil {
+		return fmt.Errorf("one-time task %s has already completed", task.Name)
+	}
+	if task.TriggerType == models.TriggerTypeFileWatch {
+		return s.addWatcher(task)
+	}
+	if isEventTriggered(task) {
+		// 启动和唤醒触发的任务不注册 cron 条目，由事件直接执行
+		s.clearNextRun(task)
+		return nil
+	}
+
 	// 创建任务执行函数
 	job := func() {
-		s.executeTask(task.ID)
+		req := runRequest{taskID: task.ID, trigger: models.TriggerSchedule}
+		if task.Jitter > 0 {
+			req.scheduledAt, req.jitter = s.plannedStart(task.ID)
+			log.Printf("Task %s starting after jitter delay of %s", task.Name, req.jitter)
+		}
+		s.executeTask(req)
 	}
 
 	// 添加到 cron
-	entryID, err := s.cron.AddFunc(task.Cron, job)
+	schedule, err := taskSchedule(task)
 	if err != nil {
 		return fmt.Errorf("failed to add cron job: %w", err)
 	}
+	entryID := s.cron.Schedule(schedule, cron.FuncJob(job))
 
 	s.jobs[task.ID] = entryID
 
 	// 更新下次运行时间
+	s.refreshNextRun(task)
+
+	if isOnce(task) {
+		log.Printf("Added job: %s (once at: %s)", task.Name, task.RunAt.Format(time.RFC3339))
+	} else {
+		log.Printf("Added job: %s (cron: %s)", task.Name, cronSpec(task))
+	}
+
+	return nil
+}
+
+// refreshNextRun 按 cron 条目更新任务的下次运行时间（以任务时区表示），调用方需持有锁
+// 在 task 的副本上修改后保存，task 应为最新的任务（执行期间使用 refreshTaskNextRun）
+func (s *Scheduler) refreshNextRun(task *models.Task) {
+	entryID, ok := s.jobs[task.ID]
+	if !ok {
+		return
+	}
+
+	// cron 启动前条目尚未计算下次时间，直接由调度计算
 	entry := s.cron.Entry(entryID)
-	nextRun := entry.Next
-	task.NextRunAt = &nextRun
+	if entry.Schedule == nil {
+		return
+	}
+	next := entry.Next
+	if next.IsZero() {
+		next = entry.Schedule.Next(time.Now())
+	}
+	next = s.nextAllowed(task, next, entry.Schedule.Next)
 
-	if err := s.storage.SaveTask(task); err != nil {
+	updated := *task
+	updated.NextRunAt = nil
+	if !next.IsZero() {
+		nextRun := next.In(taskLocation(task))
+		updated.NextRunAt = &nextRun
+	}
+	if err := s.storage.SaveTask(&updated); err != nil {
 		log.Printf("Failed to update task next run time: %v", err)
 	}
+}
+
+// refreshTaskNextRun 以存储中的最新任务更新下次运行时间，调用方需持有锁
+func (s *Scheduler) refreshTaskNextRun(taskID string) {
+	task, err := s.storage.GetTask(taskID)
+	if err != nil {
+		log.Printf("Failed to get task %s: %v", taskID, err)
+		return
+	}
+	s.refreshNextRun(task)
+}
 
-	log.Printf("Added job: %s (cron: %s)", task.Name, task.Cron)
+// clearNextRun 清除非定时触发任务的下次运行时间，调用方需持有锁
+func (s *Scheduler) clearNextRun(task *models.Task) {
+	if task.NextRunAt == nil {
+		return
+	}
+	updated := *task
+	updated.NextRunAt = nil
+	if err := s.storage.SaveTask(&updated); err != nil {
+		log.Printf("Failed to update task next run time: %v", err)
+	}
+}
 
-	return nil
+// taskSchedule 返回任务的调度，设置了随机延迟时叠加延迟
+func taskSchedule(task *models.Task) (cron.Schedule, error) {
+	var schedule cron.Schedule
+	if isOnce(task) {
+		if task.RunAt == nil {
+			return nil, fmt.Errorf("one-time task %s has no run time", task.Name)
+		}
+		schedule = onceSchedule{at: *task.RunAt}
+	} else {
+		parsed, err := cronParser.Parse(cronSpec(task))
+		if err != nil {
+			return nil, err
+		}
+		schedule = parsed
+	}
+
+	// 复制生效期，调度不引用任务上的字段
+	if task.StartAt != nil || task.EndAt != nil {
+		window := windowSchedule{base: schedule}
+		if task.StartAt != nil {
+			start := *task.StartAt
+			window.start = &start
+		}
+		if task.EndAt != nil {
+			end := *task.EndAt
+			window.end = &end
+		}
+		schedule = window
+	}
+
+	if task.Jitter > 0 {
+		schedule = jitterSchedule{
+			base:   schedule,
+			taskID: task.ID,
+			window: time.Duration(task.Jitter) * time.Second,
+		}
+	}
+	return schedule, nil
+}
+
+// cronSpec 返回任务实际注册的 cron 表达式，设置了时区时添加 CRON_TZ 前缀
+func cronSpec(task *models.Task) string {
+	if task.Timezone == "" || strings.HasPrefix(task.Cron, "CRON_TZ=") || strings.HasPrefix(task.Cron, "TZ=") {
+		return task.Cron
+	}
+	return "CRON_TZ=" + task.Timezone + " " + task.Cron
+}
+
+// taskLocation 返回任务的时区，未设置或无效时使用本地时区
+func taskLocation(task *models.Task) *time.Location {
+	if task.Timezone != "" {
+		if loc, err := time.LoadLocation(task.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
 }
 
 // removeJob 移除任务（内部方法，不加锁）
 func (s *Scheduler) removeJob(taskID string) error {
+	s.removeWatcher(taskID)
 	if entryID, ok := s.jobs[taskID]; ok {
 		s.cron.Remove(entryID)
 		delete(s.jobs, taskID)
@@ -151,40 +412,314 @@ func (s *Scheduler) removeJob(taskID string) error {
 	return nil
 }
 
-// executeTask 执行任务
-func (s *Scheduler) executeTask(taskID string) {
-	// 获取任务
+// runRequest 一次触发的执行请求
+type runRequest struct {
+	taskID      string
+	trigger     models.TriggerSource
+	triggeredBy string            // 上游执行的日志ID
+	scheduledAt time.Time         // 计划触发时间，零值表示立即触发
+	jitter      time.Duration     // 计划触发时间中包含的随机延迟
+	info        string            // 触发详情，记录到日志
+	env         map[string]string // 追加给脚本的环境变量
+	input       []byte            // 写入输入文件传给脚本的数据
+	params      map[string]string // 覆盖的参数值
+}
+
+// executeTask 执行任务（按重叠策略处理正在运行的情况）
+func (s *Scheduler) executeTask(req runRequest) {
+	taskID := req.taskID
 	task, err := s.storage.GetTask(taskID)
 	if err != nil {
 		log.Printf("Failed to get task %s: %v", taskID, err)
 		return
 	}
 
-	log.Printf("Executing task: %s", task.Name)
+	// 一次性任务的计划触发只发生一次，处理完后停用
+	if isOnce(task) && countsRun(req) {
+		defer s.completeOnce(taskID)
+	}
 
-	// 更新最后运行时间
-	now := time.Now()
-	task.LastRunAt = &now
-	if err := s.storage.SaveTask(task); err != nil {
-		log.Printf("Failed to update task last run time: %v", err)
+	// 达到最多执行次数后不再执行补跑等积压的定时触发
+	if countsRun(req) && task.MaxRuns > 0 && task.RunCount >= task.MaxRuns {
+		log.Printf("Task %s reached its max runs, skipped", task.Name)
+		return
+	}
+
+	// 定时触发落在排除日历内时跳过
+	if countsRun(req) {
+		at := req.scheduledAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if excluded, reason := s.blackout(task, at); excluded {
+			log.Printf("Task %s skipped by blackout (%s)", task.Name, reason)
+			s.recordSkip(task, "skipped: blackout ("+reason+")")
+			s.mu.RLock()
+			s.refreshTaskNextRun(taskID)
+			s.mu.RUnlock()
+			return
+		}
 	}
 
-	// 获取关联的脚本
-	script, err := s.storage.GetScript(task.ScriptID)
+	switch s.tryStart(task, req) {
+	case startSkipped:
+		log.Printf("Task %s is still running, skipped", task.Name)
+		s.recordSkip(task, "skipped: previous run still in progress")
+		s.mu.RLock()
+		s.refreshTaskNextRun(taskID)
+		s.mu.RUnlock()
+		return
+	case startQueued:
+		log.Printf("Task %s is still running, queued", task.Name)
+		s.mu.RLock()
+		s.refreshTaskNextRun(taskID)
+		s.mu.RUnlock()
+		return
+	}
+
+	// 依次执行本次请求和期间排队的请求
+	counted := false
+	for {
+		counted = counted || countsRun(req)
+		if taskLog := s.runTask(req); taskLog != nil {
+			s.triggerDownstream(taskID, taskLog)
+		}
+		next, ok := s.finish(taskID)
+		if !ok {
+			break
+		}
+		req = next
+	}
+
+	// 达到最多执行次数或已过失效时间时停用
+	if counted {
+		if current, err := s.storage.GetTask(taskID); err == nil {
+			if expired, reason := current.Expired(time.Now()); expired {
+				s.expire(taskID, reason)
+			}
+		}
+	}
+}
+
+// markStarted 在任务副本上记录开始执行的时间，count 为 true 时同时累加定时执行次数
+func (s *Scheduler) markStarted(taskID string, at time.Time, count bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.storage.GetTask(taskID)
 	if err != nil {
-		log.Printf("Failed to get script for task %s: %v", task.Name, err)
 		return
 	}
+	task := *current
+	task.LastRunAt = &at
+	if count {
+		task.RunCount++
+	}
+	if err := s.storage.SaveTask(&task); err != nil {
+		log.Printf("Failed to update task %s run state: %v", task.Name, err)
+	}
+}
+
+// recordSkip 记录一次被跳过的执行
+func (s *Scheduler) recordSkip(task *models.Task, reason string) {
+	now := time.Now()
+	taskLog := &models.TaskLog{
+		ID:        uuid.New().String(),
+		TaskID:    task.ID,
+		TaskName:  task.Name,
+		StartTime: now,
+		EndTime:   now,
+		Output:    reason,
+		Status:    models.LogStatusSkipped,
+	}
+
+	if err := s.storage.SaveLog(taskLog); err != nil {
+		log.Printf("Failed to save task log: %v", err)
+	}
+}
+
+// recordCancelled 记录一次在排队期间被取消的执行
+func (s *Scheduler) recordCancelled(runID string, task *models.Task) {
+	now := time.Now()
+	taskLog := &models.TaskLog{
+		ID:        runID,
+		TaskID:    task.ID,
+		TaskName:  task.Name,
+		StartTime: now,
+		EndTime:   now,
+		Error:     "cancelled by user",
+		Status:    models.LogStatusCancelled,
+	}
+
+	if err := s.storage.SaveLog(taskLog); err != nil {
+		log.Printf("Failed to save task log: %v", err)
+	}
+}
+
+// runTask 执行一次任务（失败时按重试策略重试），返回最终的执行日志
+func (s *Scheduler) runTask(req runRequest) *models.TaskLog {
+	taskID := req.taskID
+	var task *models.Task
+	var taskLog *models.TaskLog
+	parentRunID := ""
+
+	// 前置条件不成立时记录跳过，手动执行不检查
+	if req.trigger != models.TriggerManual {
+		if current, err := s.storage.GetTask(taskID); err == nil && len(current.Conditions) > 0 {
+			if ok, detail := s.checkConditions(current); !ok {
+				log.Printf("Task %s skipped, precondition false (%s)", current.Name, detail)
+				s.recordSkip(current, "skipped: precondition false ("+detail+")")
+				s.mu.RLock()
+				s.refreshTaskNextRun(taskID)
+				s.mu.RUnlock()
+				return nil
+			}
+		}
+	}
+
+	// 输入数据写入临时文件，各次尝试共用，结束后删除
+	if req.input != nil {
+		path, err := writeInputFile(req.input)
+		if err != nil {
+			log.Printf("Failed to write input file for task %s: %v", taskID, err)
+		} else {
+			defer os.Remove(path)
+			env := map[string]string{"TEMPO_INPUT_FILE": path}
+			for key, value := range req.env {
+				env[key] = value
+			}
+			req.env = env
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		// 每次尝试前重新获取任务，任务被删除时停止重试
+		current, err := s.storage.GetTask(taskID)
+		if err != nil {
+			log.Printf("Failed to get task %s: %v", taskID, err)
+			break
+		}
+		task = current
+
+		attemptLog := s.runAttempt(task, req, parentRunID, attempt)
+		if attemptLog == nil {
+			break
+		}
+		taskLog = attemptLog
+		if parentRunID == "" {
+			parentRunID = taskLog.ID
+		}
+
+		delay, retry := nextRetry(task.Retry, attempt, taskLog)
+		if !retry {
+			break
+		}
+		log.Printf("Task %s failed (attempt %d/%d), retrying in %s", task.Name, attempt, task.Retry.MaxAttempts, delay)
+		if !s.waitRetry(taskLog, delay) {
+			break
+		}
+	}
+
+	if taskLog == nil {
+		return nil
+	}
+
+	// 执行期间任务已被更新，使用最新的任务
+	if current, err := s.storage.GetTask(taskID); err == nil {
+		task = current
+	}
+
+	// 只在最后一次尝试后发送通知
+	s.notifyTask(task, taskLog)
 
-	// 执行任务
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	// 更新下次运行时间
+	s.mu.RLock()
+	s.refreshTaskNextRun(taskID)
+	s.mu.RUnlock()
 
-	taskLog, err := s.executor.ExecuteTask(ctx, task, script)
+	return taskLog
+}
+
+// writeInputFile 将输入数据写入临时文件，返回文件路径
+func writeInputFile(data []byte) (string, error) {
+	file, err := os.CreateTemp("", "tempo-input-*")
 	if err != nil {
-		log.Printf("Task execution failed: %s - %v", task.Name, err)
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// runAttempt 执行一次尝试并保存日志
+func (s *Scheduler) runAttempt(task *models.Task, req runRequest, parentRunID string, attempt int) *models.TaskLog {
+	// 等待执行槽位
+	runID := uuid.New().String()
+	if !s.pool.acquire(RunInfo{
+		RunID:    runID,
+		TaskID:   task.ID,
+		TaskName: task.Name,
+		QueuedAt: time.Now(),
+	}) {
+		s.recordCancelled(runID, task)
+		return nil
+	}
+	defer s.pool.release(runID)
+
+	log.Printf("Executing task: %s (attempt %d)", task.Name, attempt)
+
+	// 更新最后运行时间，定时触发的首次尝试计入执行次数
+	s.markStarted(task.ID, time.Now(), attempt == 1 && countsRun(req))
+
+	params := resolveParams(task, req.params)
+	env, args := paramInput(task, params)
+	for key, value := range req.env {
+		if env == nil {
+			env = make(map[string]string, len(req.env))
+		}
+		env[key] = value
+	}
+	input := executor.RunInput{Env: env, Args: args}
+
+	var taskLog *models.TaskLog
+	var script *models.Script
+	if task.IsWorkflow() {
+		taskLog = s.runWorkflow(runID, task, input)
+		log.Printf("Workflow finished: %s (%s)", task.Name, taskLog.Status)
 	} else {
-		log.Printf("Task executed successfully: %s", task.Name)
+		// 获取关联的脚本
+		var err error
+		script, err = s.storage.GetScript(task.ScriptID)
+		if err != nil {
+			log.Printf("Failed to get script for task %s: %v", task.Name, err)
+			return nil
+		}
+
+		// 执行任务
+		ctx, cancel := withTimeout(s.storage.GetSettings().TimeoutFor(task, script))
+		defer cancel()
+
+		taskLog, err = s.executor.ExecuteTask(ctx, runID, task, script, input)
+		if err != nil {
+			log.Printf("Task execution failed: %s - %v", task.Name, err)
+		} else {
+			log.Printf("Task executed successfully: %s", task.Name)
+		}
+	}
+	taskLog.Attempt = attempt
+	taskLog.ParentRunID = parentRunID
+	taskLog.Trigger = req.trigger
+	taskLog.TriggeredBy = req.triggeredBy
+	taskLog.TriggerInfo = req.info
+	taskLog.JitterDelay = int(req.jitter / time.Second)
+	taskLog.Params = params
+	if !req.scheduledAt.IsZero() {
+		scheduledAt := req.scheduledAt
+		taskLog.ScheduledAt = &scheduledAt
 	}
 
 	// 保存日志
@@ -192,34 +727,95 @@ func (s *Scheduler) executeTask(taskID string) {
 		log.Printf("Failed to save task log: %v", err)
 	}
 
-	// 更新脚本最后运行时间
-	scriptNow := time.Now()
-	script.LastRunAt = &scriptNow
-	if err := s.storage.SaveScript(script); err != nil {
+	// 更新脚本最后运行时间，工作流在各步骤结束时更新
+	if script != nil {
+		s.touchScript(script)
+	}
+
+	return taskLog
+}
+
+// touchScript 更新脚本最后运行时间
+// 工作流的并行步骤可能同时更新同一脚本，修改副本后保存，不改动存储中共享的脚本
+func (s *Scheduler) touchScript(script *models.Script) {
+	now := time.Now()
+	updated := *script
+	updated.LastRunAt = &now
+	if err := s.storage.SaveScript(&updated); err != nil {
 		log.Printf("Failed to update script last run time: %v", err)
 	}
+}
 
-	// 更新下次运行时间
+// notifyTask 根据任务的通知策略发送通知
+func (s *Scheduler) notifyTask(task *models.Task, taskLog *models.TaskLog) {
 	s.mu.RLock()
-	if entryID, ok := s.jobs[taskID]; ok {
-		entry := s.cron.Entry(entryID)
-		nextRun := entry.Next
-		task.NextRunAt = &nextRun
-		if err := s.storage.SaveTask(task); err != nil {
-			log.Printf("Failed to update task next run time: %v", err)
-		}
-	}
+	notify := s.notify
 	s.mu.RUnlock()
+
+	if notify == nil || !shouldNotify(task.NotifyOn, taskLog.Success) {
+		return
+	}
+	notify(taskLog)
+}
+
+// shouldNotify 判断执行结果是否需要通知，未设置策略的任务（包括旧版本保存的任务）仅失败时通知
+func shouldNotify(policy models.NotifyPolicy, success bool) bool {
+	switch policy {
+	case models.NotifyAlways:
+		return true
+	case models.NotifyOnFailure, "":
+		return !success
+	case models.NotifyOnSuccess:
+		return success
+	default:
+		return false
+	}
+}
+
+// withTimeout 创建带超时的 context，timeout <= 0 表示不限制
+func withTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// RunOptions 立即运行时的附加信息
+type RunOptions struct {
+	Trigger models.TriggerSource // 触发来源，为空表示手动执行
+	Info    string               // 触发详情，记录到日志
+	Env     map[string]string    // 追加给脚本的环境变量
+	Input   []byte               // 写入临时文件的输入数据，文件路径通过 TEMPO_INPUT_FILE 传给脚本
+	Params  map[string]string    // 覆盖的参数值，只能覆盖任务已定义的参数
 }
 
 // RunTaskNow 立即运行任务
 func (s *Scheduler) RunTaskNow(taskID string) error {
+	return s.RunTask(taskID, RunOptions{})
+}
+
+// RunTask 立即运行任务，不受暂停影响
+func (s *Scheduler) RunTask(taskID string, opts RunOptions) error {
 	task, err := s.storage.GetTask(taskID)
 	if err != nil {
 		return err
 	}
+	if err := checkOverrides(task, opts.Params); err != nil {
+		return err
+	}
 
-	go s.executeTask(task.ID)
+	trigger := opts.Trigger
+	if trigger == "" {
+		trigger = models.TriggerManual
+	}
+	go s.executeTask(runRequest{
+		taskID:  task.ID,
+		trigger: trigger,
+		info:    opts.Info,
+		env:     opts.Env,
+		input:   opts.Input,
+		params:  opts.Params,
+	})
 	return nil
 }
 
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tempo/internal/models"
+)
+
+// BuildCron 根据任务的调度类型和时间配置生成 cron 表达式，自定义类型直接校验并返回 task.Cron
+// 一次性任务和非定时触发的任务不使用 cron 表达式，只校验执行时间或触发配置并返回空字符串
+func BuildCron(task *models.Task) (string, error) {
+	switch task.TriggerType {
+	case models.TriggerTypeFileWatch:
+		return "", validateFileWatch(task.FileWatch)
+	case models.TriggerTypeStartup, models.TriggerTypeResume:
+		if task.TriggerDelay < 0 {
+			return "", fmt.Errorf("trigger delay must not be negative")
+		}
+		return "", nil
+	case "", models.TriggerTypeSchedule:
+	default:
+		return "", fmt.Errorf("unsupported trigger type: %s", task.TriggerType)
+	}
+
+	if isOnce(task) {
+		if err := validateOnce(task, time.Now()); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+
+	if task.ScheduleType == models.ScheduleTypeCustom {
+		if err := ParseCron(task.Cron, task.Timezone); err != nil {
+			return "", err
+		}
+		return task.Cron, nil
+	}
+
+	expr, err := cronFromTimeConfig(task.ScheduleType, task.TimeConfig)
+	if err != nil {
+		return "", err
+	}
+	if err := ParseCron(expr, task.Timezone); err != nil {
+		return "", err
+	}
+	return expr, nil
+}
+
+// cronFromTimeConfig 将时间配置转换为 cron 表达式
+func cronFromTimeConfig(scheduleType models.ScheduleType, tc models.TimeConfig) (string, error) {
+	if scheduleType == models.ScheduleTypeInterval {
+		return intervalCron(tc.Interval)
+	}
+
+	if tc.Hour < 0 || tc.Hour > 23 {
+		return "", fmt.Errorf("invalid hour %d: must be 0-23", tc.Hour)
+	}
+	if tc.Minute < 0 || tc.Minute > 59 {
+		return "", fmt.Errorf("invalid minute %d: must be 0-59", tc.Minute)
+	}
+
+	switch scheduleType {
+	case models.ScheduleTypeDaily:
+		return fmt.Sprintf("0 %d %d * * *", tc.Minute, tc.Hour), nil
+
+	case models.ScheduleTypeWeekly:
+		days := tc.Weekdays
+		if len(days) == 0 {
+			days = []int{tc.Weekday}
+		}
+		parts := make([]string, 0, len(days))
+		for _, day := range days {
+			if day < 0 || day > 6 {
+				return "", fmt.Errorf("invalid weekday %d: must be 0-6", day)
+			}
+			parts = append(parts, strconv.Itoa(day))
+		}
+		return fmt.Sprintf("0 %d %d * * %s", tc.Minute, tc.Hour, strings.Join(parts, ",")), nil
+
+	case models.ScheduleTypeMonthly:
+		if tc.Monthday < 1 || tc.Monthday > 31 {
+			return "", fmt.Errorf("invalid day of month %d: must be 1-31", tc.Monthday)
+		}
+		return fmt.Sprintf("0 %d %d %d * *", tc.Minute, tc.Hour, tc.Monthday), nil
+
+	case models.ScheduleTypeLastDayOfMonth:
+		return fmt.Sprintf("0 %d %d L * *", tc.Minute, tc.Hour), nil
+
+	case models.ScheduleTypeNthWeekday:
+		if tc.Weekday < 0 || tc.Weekday > 6 {
+			return "", fmt.Errorf("invalid weekday %d: must be 0-6", tc.Weekday)
+		}
+		switch {
+		case tc.Nth == -1:
+			return fmt.Sprintf("0 %d %d * * %dL", tc.Minute, tc.Hour, tc.Weekday), nil
+		case tc.Nth >= 1 && tc.Nth <= 5:
+			return fmt.Sprintf("0 %d %d * * %d#%d", tc.Minute, tc.Hour, tc.Weekday, tc.Nth), nil
+		default:
+			return "", fmt.Errorf("invalid occurrence %d: must be 1-5 or -1 for the last one", tc.Nth)
+		}
+
+	default:
+		return "", fmt.Errorf("unknown schedule type: %q", scheduleType)
+	}
+}
+
+// intervalCron 每隔 N 分钟执行的表达式，能整除小时或天时对齐到整点
+func intervalCron(minutes int) (string, error) {
+	if minutes <= 0 {
+		return "", fmt.Errorf("invalid interval %d: must be at least 1 minute", minutes)
+	}
+
+	switch {
+	case minutes < 60 && 60%minutes == 0:
+		return fmt.Sprintf("0 */%d * * * *", minutes), nil
+	case minutes%60 == 0 && minutes < 24*60 && (24*60)%minutes == 0:
+		return fmt.Sprintf("0 0 */%d * * *", minutes/60), nil
+	case minutes == 24*60:
+		return "0 0 0 * * *", nil
+	default:
+		return fmt.Sprintf("@every %dm", minutes), nil
+	}
+}
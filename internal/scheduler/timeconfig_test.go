@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"testing"
+
+	"tempo/internal/models"
+)
+
+func TestBuildCron(t *testing.T) {
+	tests := []struct {
+		name         string
+		scheduleType models.ScheduleType
+		cron         string
+		tc           models.TimeConfig
+		want         string
+		wantErr      bool
+	}{
+		{name: "daily", scheduleType: models.ScheduleTypeDaily, tc: models.TimeConfig{Hour: 9, Minute: 30}, want: "0 30 9 * * *"},
+		{name: "daily invalid hour", scheduleType: models.ScheduleTypeDaily, tc: models.TimeConfig{Hour: 24}, wantErr: true},
+		{name: "daily invalid minute", scheduleType: models.ScheduleTypeDaily, tc: models.TimeConfig{Minute: -1}, wantErr: true},
+		{name: "weekly single", scheduleType: models.ScheduleTypeWeekly, tc: models.TimeConfig{Hour: 8, Weekday: 1}, want: "0 0 8 * * 1"},
+		{name: "weekly multiple", scheduleType: models.ScheduleTypeWeekly, tc: models.TimeConfig{Hour: 8, Weekday: 3, Weekdays: []int{1, 3, 5}}, want: "0 0 8 * * 1,3,5"},
+		{name: "weekly invalid day", scheduleType: models.ScheduleTypeWeekly, tc: models.TimeConfig{Weekdays: []int{1, 7}}, wantErr: true},
+		{name: "monthly", scheduleType: models.ScheduleTypeMonthly, tc: models.TimeConfig{Hour: 1, Minute: 5, Monthday: 15}, want: "0 5 1 15 * *"},
+		{name: "monthly invalid day", scheduleType: models.ScheduleTypeMonthly, tc: models.TimeConfig{Monthday: 0}, wantErr: true},
+		{name: "last day of month", scheduleType: models.ScheduleTypeLastDayOfMonth, tc: models.TimeConfig{Hour: 23, Minute: 59}, want: "0 59 23 L * *"},
+		{name: "nth weekday", scheduleType: models.ScheduleTypeNthWeekday, tc: models.TimeConfig{Hour: 10, Weekday: 2, Nth: 3}, want: "0 0 10 * * 2#3"},
+		{name: "last weekday", scheduleType: models.ScheduleTypeNthWeekday, tc: models.TimeConfig{Hour: 10, Weekday: 5, Nth: -1}, want: "0 0 10 * * 5L"},
+		{name: "nth weekday invalid occurrence", scheduleType: models.ScheduleTypeNthWeekday, tc: models.TimeConfig{Weekday: 1, Nth: 6}, wantErr: true},
+		{name: "interval", scheduleType: models.ScheduleTypeInterval, tc: models.TimeConfig{Hour: 5, Interval: 30}, want: "0 */30 * * * *"},
+		{name: "interval missing", scheduleType: models.ScheduleTypeInterval, wantErr: true},
+		{name: "custom", scheduleType: models.ScheduleTypeCustom, cron: "*/5 * * * *", want: "*/5 * * * *"},
+		{name: "custom invalid", scheduleType: models.ScheduleTypeCustom, cron: "* * *", wantErr: true},
+		{name: "unknown type", scheduleType: "hourly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildCron(&models.Task{ScheduleType: tt.scheduleType, Cron: tt.cron, TimeConfig: tt.tc})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BuildCron = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildCron: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildCron = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalCron(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+		wantErr bool
+	}{
+		{minutes: 0, wantErr: true},
+		{minutes: -5, wantErr: true},
+		{minutes: 1, want: "0 */1 * * * *"},
+		{minutes: 15, want: "0 */15 * * * *"},
+		{minutes: 7, want: "@every 7m"},
+		{minutes: 60, want: "0 0 */1 * * *"},
+		{minutes: 90, want: "@every 90m"},
+		{minutes: 360, want: "0 0 */6 * * *"},
+		{minutes: 300, want: "@every 300m"},
+		{minutes: 24 * 60, want: "0 0 0 * * *"},
+		{minutes: 48 * 60, want: "@every 2880m"},
+	}
+
+	for _, tt := range tests {
+		got, err := intervalCron(tt.minutes)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("intervalCron(%d) = %q, want error", tt.minutes, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("intervalCron(%d): %v", tt.minutes, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("intervalCron(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+		// 生成的表达式必须能被调度器解析
+		if err := ParseCron(got, ""); err != nil {
+			t.Errorf("ParseCron(%q): %v", got, err)
+		}
+	}
+}
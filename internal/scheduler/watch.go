@@ -0,0 +1,329 @@
+package scheduler
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultDebounce 文件监视的默认防抖时间
+const defaultDebounce = time.Second
+
+// watchOps 可配置的文件事件
+var watchOps = map[string]fsnotify.Op{
+	"create": fsnotify.Create,
+	"write":  fsnotify.Write,
+	"remove": fsnotify.Remove,
+	"rename": fsnotify.Rename,
+}
+
+// validateFileWatch 校验文件监视配置
+func validateFileWatch(config models.FileWatch) error {
+	if len(config.Paths) == 0 {
+		return fmt.Errorf("at least one path to watch is required")
+	}
+	for _, path := range config.Paths {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("watch path %q must be absolute", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("watch path %q: %w", path, err)
+		}
+	}
+	for _, pattern := range config.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	for _, event := range config.Events {
+		if _, ok := watchOps[event]; !ok {
+			return fmt.Errorf("unsupported watch event %q", event)
+		}
+	}
+	if config.Debounce < 0 {
+		return fmt.Errorf("debounce must not be negative")
+	}
+	return nil
+}
+
+// pendingChange 防抖期间等待触发的文件变化
+type pendingChange struct {
+	op    fsnotify.Op
+	timer *time.Timer
+}
+
+// fileWatcher 单个任务的文件监视器
+type fileWatcher struct {
+	config   models.FileWatch
+	watcher  *fsnotify.Watcher
+	ops      fsnotify.Op
+	debounce time.Duration
+	roots    []string        // 监视的目录
+	files    map[string]bool // 监视的单个文件（通过监视所在目录实现）
+	fire     func(path string, op fsnotify.Op)
+
+	mu      sync.Mutex
+	pending map[string]*pendingChange
+	done    chan struct{}
+}
+
+// newFileWatcher 按配置创建文件监视器，变化在防抖后通过 fire 回调
+func newFileWatcher(config models.FileWatch, fire func(path string, op fsnotify.Op)) (*fileWatcher, error) {
+	if err := validateFileWatch(config); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	fw := &fileWatcher{
+		config:   config,
+		watcher:  watcher,
+		debounce: time.Duration(config.Debounce) * time.Millisecond,
+		files:    make(map[string]bool),
+		fire:     fire,
+		pending:  make(map[string]*pendingChange),
+		done:     make(chan struct{}),
+	}
+	if fw.debounce <= 0 {
+		fw.debounce = defaultDebounce
+	}
+	for _, event := range config.Events {
+		fw.ops |= watchOps[event]
+	}
+	if fw.ops == 0 {
+		fw.ops = fsnotify.Create | fsnotify.Write
+	}
+
+	for _, path := range config.Paths {
+		path = filepath.Clean(path)
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			fw.roots = append(fw.roots, path)
+			err = fw.addDir(path)
+		} else if err == nil {
+			// 直接监视文件在文件被替换（如编辑器保存）后会失效，改为监视所在目录
+			fw.files[path] = true
+			err = watcher.Add(filepath.Dir(path))
+		}
+		if err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+	}
+
+	go fw.loop()
+	return fw, nil
+}
+
+// addDir 监视目录，递归时同时监视所有子目录
+func (fw *fileWatcher) addDir(dir string) error {
+	if !fw.config.Recursive {
+		return fw.watcher.Add(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return fw.watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// loop 处理文件事件直到监视器关闭
+func (fw *fileWatcher) loop() {
+	for {
+		select {
+		case <-fw.done:
+			return
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			fw.handle(event)
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("File watcher error: %v", err)
+		}
+	}
+}
+
+// handle 过滤事件并加入防抖队列
+func (fw *fileWatcher) handle(event fsnotify.Event) {
+	// 递归监视时为新建的子目录添加监视，并补发监视建立前已写入的文件
+	if fw.config.Recursive && event.Has(fsnotify.Create) && fw.underRoot(event.Name) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := fw.addDir(event.Name); err != nil {
+				log.Printf("Failed to watch new directory %s: %v", event.Name, err)
+			}
+			filepath.WalkDir(event.Name, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && fw.matches(path) {
+					fw.schedule(path, fsnotify.Create&fw.ops)
+				}
+				return nil
+			})
+		}
+	}
+
+	op := event.Op & fw.ops
+	if op == 0 || !fw.matches(event.Name) {
+		return
+	}
+	fw.schedule(event.Name, op)
+}
+
+// underRoot 路径是否位于监视的目录中（不递归时只包含直接子项）
+func (fw *fileWatcher) underRoot(path string) bool {
+	for _, root := range fw.roots {
+		if fw.config.Recursive {
+			rel, err := filepath.Rel(root, path)
+			if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+				return true
+			}
+		} else if filepath.Dir(path) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// matches 路径是否需要触发：属于监视的文件或目录，且匹配通配符
+// 通配符包含路径分隔符时匹配完整路径，否则匹配文件名
+func (fw *fileWatcher) matches(path string) bool {
+	if !fw.files[path] && !fw.underRoot(path) {
+		return false
+	}
+	if len(fw.config.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range fw.config.Patterns {
+		target := filepath.Base(path)
+		if strings.ContainsRune(pattern, filepath.Separator) {
+			target = path
+		}
+		if ok, _ := filepath.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule 同一文件在防抖时间内的连续变化合并为一次触发
+// 新建后紧接着的写入仍按新建报告
+func (fw *fileWatcher) schedule(path string, op fsnotify.Op) {
+	if op == 0 {
+		return
+	}
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if change, ok := fw.pending[path]; ok {
+		if !(change.op.Has(fsnotify.Create) && op == fsnotify.Write) {
+			change.op = op
+		}
+		change.timer.Reset(fw.debounce)
+		return
+	}
+	fw.pending[path] = &pendingChange{
+		op:    op,
+		timer: time.AfterFunc(fw.debounce, func() { fw.flush(path) }),
+	}
+}
+
+// flush 防抖时间结束后触发
+func (fw *fileWatcher) flush(path string) {
+	fw.mu.Lock()
+	change, ok := fw.pending[path]
+	delete(fw.pending, path)
+	fw.mu.Unlock()
+
+	if ok {
+		fw.fire(path, change.op)
+	}
+}
+
+// close 停止监视并丢弃尚未触发的变化
+func (fw *fileWatcher) close() {
+	close(fw.done)
+	fw.watcher.Close()
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	for path, change := range fw.pending {
+		change.timer.Stop()
+		delete(fw.pending, path)
+	}
+}
+
+// opName 返回事件名称
+func opName(op fsnotify.Op) string {
+	for _, name := range []string{"create", "write", "remove", "rename"} {
+		if op.Has(watchOps[name]) {
+			return name
+		}
+	}
+	return op.String()
+}
+
+// addWatcher 为文件监视任务注册监视器（内部方法，不加锁）
+func (s *Scheduler) addWatcher(task *models.Task) error {
+	taskID := task.ID
+	fw, err := newFileWatcher(task.FileWatch, func(path string, op fsnotify.Op) {
+		s.fileChanged(taskID, path, op)
+	})
+	if err != nil {
+		return err
+	}
+	s.watchers[taskID] = fw
+
+	// 文件监视任务没有下次运行时间
+	s.clearNextRun(task)
+
+	log.Printf("Added watcher: %s (paths: %s)", task.Name, strings.Join(task.FileWatch.Paths, ", "))
+	return nil
+}
+
+// removeWatcher 移除任务的文件监视器（内部方法，不加锁）
+func (s *Scheduler) removeWatcher(taskID string) {
+	if fw, ok := s.watchers[taskID]; ok {
+		fw.close()
+		delete(s.watchers, taskID)
+		log.Printf("Removed watcher: %s", taskID)
+	}
+}
+
+// fileChanged 监视的文件变化后执行任务，变化的文件通过环境变量传给脚本
+func (s *Scheduler) fileChanged(taskID, path string, op fsnotify.Op) {
+	if paused, _ := s.IsPaused(); paused {
+		log.Printf("Scheduler paused, ignoring change of %s", path)
+		return
+	}
+
+	log.Printf("File %s: %s, triggering task %s", opName(op), path, taskID)
+	s.executeTask(runRequest{
+		taskID:  taskID,
+		trigger: models.TriggerFileWatch,
+		info:    path,
+		env: map[string]string{
+			"TEMPO_TRIGGER":    string(models.TriggerFileWatch),
+			"TEMPO_EVENT_PATH": path,
+			"TEMPO_EVENT_NAME": filepath.Base(path),
+			"TEMPO_EVENT_OP":   opName(op),
+		},
+	})
+}
@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// watchdogInterval 看门狗检查间隔
+const watchdogInterval = 30 * time.Second
+
+// watchdog 定期检查设置了看门狗规则的任务：下次运行时间过后宽限期内没有执行、或执行超过预期时长时告警
+func (s *Scheduler) watchdog(stop <-chan struct{}) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	missed := make(map[string]time.Time) // 任务ID -> 已告警的计划时间
+	overdue := make(map[string]bool)     // 已告警的运行ID
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			// 暂停期间不检查错过的执行
+			if paused, _ := s.IsPaused(); !paused {
+				for _, taskLog := range s.checkMissed(now, missed) {
+					s.alert(taskLog)
+				}
+			}
+			s.checkOverdue(now, overdue)
+		}
+	}
+}
+
+// checkMissed 检查超过宽限期仍未执行的任务并记录日志，返回需要发送的告警
+// 每个计划时间只告警一次
+func (s *Scheduler) checkMissed(now time.Time, alerted map[string]time.Time) []*models.TaskLog {
+	var alerts []*models.TaskLog
+	pending := make(map[string]bool)
+	for _, run := range append(s.pool.running(), s.pool.queued()...) {
+		pending[run.TaskID] = true
+	}
+
+	for _, task := range s.storage.GetAllTasks() {
+		grace := time.Duration(task.Watchdog.GracePeriod) * time.Second
+		if task.Status != models.TaskStatusActive || grace <= 0 || task.NextRunAt == nil || pending[task.ID] {
+			continue
+		}
+
+		expected := *task.NextRunAt
+		if now.Before(expected.Add(grace)) {
+			continue
+		}
+		if task.LastRunAt != nil && !task.LastRunAt.Before(expected) {
+			continue
+		}
+		if last, ok := alerted[task.ID]; ok && last.Equal(expected) {
+			continue
+		}
+		alerted[task.ID] = expected
+
+		log.Printf("Watchdog: task %s missed its run at %s", task.Name, expected.Format(time.RFC3339))
+		taskLog := &models.TaskLog{
+			ID:          uuid.New().String(),
+			TaskID:      task.ID,
+			TaskName:    task.Name,
+			StartTime:   now,
+			EndTime:     now,
+			Error:       fmt.Sprintf("missed run: expected at %s, nothing started within the %s grace period", expected.Format(time.RFC3339), grace),
+			Status:      models.LogStatusMissed,
+			ScheduledAt: &expected,
+		}
+		if err := s.storage.SaveLog(taskLog); err != nil {
+			log.Printf("Failed to save task log: %v", err)
+		}
+		alerts = append(alerts, taskLog)
+	}
+	return alerts
+}
+
+// checkOverdue 检查运行超过预期时长的执行，每次运行只告警一次
+func (s *Scheduler) checkOverdue(now time.Time, alerted map[string]bool) {
+	active := make(map[string]bool)
+	for _, run := range s.pool.running() {
+		active[run.RunID] = true
+		if run.TaskID == "" || run.StartedAt == nil || alerted[run.RunID] {
+			continue
+		}
+
+		task, err := s.storage.GetTask(run.TaskID)
+		if err != nil {
+			continue
+		}
+		expected := time.Duration(task.Watchdog.ExpectedDuration) * time.Second
+		elapsed := now.Sub(*run.StartedAt)
+		if expected <= 0 || elapsed <= expected {
+			continue
+		}
+		alerted[run.RunID] = true
+
+		log.Printf("Watchdog: task %s has been running for %s (expected %s)", task.Name, elapsed.Round(time.Second), expected)
+		s.alert(&models.TaskLog{
+			ID:        run.RunID,
+			TaskID:    task.ID,
+			TaskName:  task.Name,
+			StartTime: *run.StartedAt,
+			EndTime:   now,
+			Duration:  elapsed.Milliseconds(),
+			Error:     fmt.Sprintf("run exceeded its expected duration of %s (running for %s)", expected, elapsed.Round(time.Second)),
+			Status:    models.LogStatusOverdue,
+		})
+	}
+
+	// 清理已结束运行的告警记录
+	for runID := range alerted {
+		if !active[runID] {
+			delete(alerted, runID)
+		}
+	}
+}
+
+// alert 发送看门狗告警，不受任务通知策略限制
+func (s *Scheduler) alert(taskLog *models.TaskLog) {
+	s.mu.RLock()
+	notify := s.notify
+	s.mu.RUnlock()
+
+	if notify != nil {
+		notify(taskLog)
+	}
+}
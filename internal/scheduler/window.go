@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"log"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// windowSchedule 只在任务生效期 [start, end) 内触发的调度
+type windowSchedule struct {
+	base  cron.Schedule
+	start *time.Time
+	end   *time.Time
+}
+
+// Next 实现 cron.Schedule
+func (s windowSchedule) Next(t time.Time) time.Time {
+	if s.start != nil && t.Before(*s.start) {
+		t = s.start.Add(-time.Nanosecond)
+	}
+	next := s.base.Next(t)
+	if next.IsZero() || (s.end != nil && !next.Before(*s.end)) {
+		return time.Time{}
+	}
+	return next
+}
+
+// countsRun 该触发是否计入任务的定时执行次数
+func countsRun(req runRequest) bool {
+	return req.trigger == models.TriggerSchedule || req.trigger == models.TriggerCatchUp
+}
+
+// expireTasks 停用已过失效时间或达到最多执行次数的任务
+func (s *Scheduler) expireTasks(now time.Time) {
+	for _, task := range s.storage.GetAllTasks() {
+		if task.Status != models.TaskStatusActive {
+			continue
+		}
+		if expired, reason := task.Expired(now); expired {
+			s.expire(task.ID, reason)
+		}
+	}
+}
+
+// expire 停用任务并从调度中移除
+func (s *Scheduler) expire(taskID, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.storage.GetTask(taskID)
+	if err != nil || current.Status != models.TaskStatusActive {
+		return
+	}
+
+	task := *current
+	task.Status = models.TaskStatusInactive
+	task.NextRunAt = nil
+	s.removeJob(taskID)
+
+	if err := s.storage.SaveTask(&task); err != nil {
+		log.Printf("Failed to deactivate task %s: %v", task.Name, err)
+		return
+	}
+	log.Printf("Task %s deactivated: %s", task.Name, reason)
+}
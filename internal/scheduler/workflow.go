@@ -0,0 +1,400 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"tempo/internal/executor"
+	"tempo/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cleanupStepID 清理脚本在步骤日志中的标识
+const cleanupStepID = "cleanup"
+
+// workflowRun 运行中的工作流
+type workflowRun struct {
+	id        string
+	taskName  string
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
+// stepResult 步骤执行结果
+type stepResult struct {
+	index int
+	lane  bool // 是否使用工作流自身的执行槽位
+	log   models.StepLog
+}
+
+// ValidateWorkflow 校验工作流配置：步骤标识唯一，依赖的步骤存在且不形成环（不检查脚本是否存在）
+func ValidateWorkflow(wf *models.Workflow) error {
+	if wf == nil || len(wf.Steps) == 0 {
+		return nil
+	}
+
+	switch wf.OnFailure {
+	case "", models.StepFailureStop, models.StepFailureContinue:
+	case models.StepFailureCleanup:
+		if wf.CleanupID == "" {
+			return fmt.Errorf("cleanup script is required when failure handling is cleanup")
+		}
+	default:
+		return fmt.Errorf("unsupported failure handling: %s", wf.OnFailure)
+	}
+
+	ids := make(map[string]bool, len(wf.Steps))
+	for _, step := range wf.Steps {
+		switch {
+		case step.ID == "":
+			return fmt.Errorf("step id is required")
+		case step.ID == cleanupStepID:
+			return fmt.Errorf("step id %q is reserved", cleanupStepID)
+		case ids[step.ID]:
+			return fmt.Errorf("duplicate step id %q", step.ID)
+		case step.ScriptID == "":
+			return fmt.Errorf("step %s has no script", step.ID)
+		case step.Timeout < 0:
+			return fmt.Errorf("step %s timeout must not be negative", step.ID)
+		}
+		ids[step.ID] = true
+	}
+
+	for _, step := range wf.Steps {
+		for _, dep := range step.DependsOn {
+			if dep == step.ID {
+				return fmt.Errorf("step %s depends on itself", step.ID)
+			}
+			if !ids[dep] {
+				return fmt.Errorf("step %s depends on unknown step %s", step.ID, dep)
+			}
+		}
+	}
+
+	_, err := workflowOrder(wf.Steps)
+	return err
+}
+
+// workflowOrder 按依赖关系返回步骤的拓扑顺序，同一层级保持定义顺序，存在环时返回错误
+func workflowOrder(steps []models.WorkflowStep) ([]string, error) {
+	pending := make(map[string]int, len(steps))
+	dependents := make(map[string][]string)
+	for _, step := range steps {
+		pending[step.ID] = len(step.DependsOn)
+		for _, dep := range step.DependsOn {
+			dependents[dep] = append(dependents[dep], step.ID)
+		}
+	}
+
+	var order []string
+	for _, step := range steps {
+		if pending[step.ID] == 0 {
+			order = append(order, step.ID)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, next := range dependents[order[i]] {
+			if pending[next]--; pending[next] == 0 {
+				order = append(order, next)
+			}
+		}
+	}
+
+	if len(order) < len(steps) {
+		var cyclic []string
+		for _, step := range steps {
+			if pending[step.ID] > 0 {
+				cyclic = append(cyclic, step.ID)
+			}
+		}
+		return nil, fmt.Errorf("workflow steps contain a cycle: %s", strings.Join(cyclic, ", "))
+	}
+	return order, nil
+}
+
+// runWorkflow 执行工作流：依赖全部成功的步骤立即启动，无依赖关系的分支并行执行
+// 工作流自身占用的槽位供一个步骤使用，并行的其他步骤各自占用执行池的空闲槽位，没有空闲槽位时等待其他步骤结束
+// 返回汇总的执行日志，各步骤的日志按定义顺序记录在 Steps 中
+func (s *Scheduler) runWorkflow(runID string, task *models.Task, input executor.RunInput) *models.TaskLog {
+	wf := task.Workflow
+	startTime := time.Now()
+
+	// 任务超时时间限制整个工作流，步骤超时使用步骤或脚本设置
+	ctx, cancel := withTimeout(time.Duration(task.Timeout) * time.Second)
+	defer cancel()
+
+	run := &workflowRun{id: runID, taskName: task.Name, cancel: cancel}
+	s.workflowMu.Lock()
+	s.workflows[runID] = run
+	s.workflowMu.Unlock()
+	defer func() {
+		s.workflowMu.Lock()
+		delete(s.workflows, runID)
+		s.workflowMu.Unlock()
+	}()
+
+	// 步骤的输出转发到工作流的运行ID，以便实时查看整个工作流
+	defer s.executor.Track(runID)()
+
+	policy := wf.OnFailure
+	if policy == "" {
+		policy = models.StepFailureStop
+	}
+
+	steps := wf.Steps
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		index[step.ID] = i
+	}
+
+	logs := make([]models.StepLog, len(steps))
+	started := make([]bool, len(steps))
+	done := make([]bool, len(steps))
+	results := make(chan stepResult)
+	running := 0
+	laneBusy := false
+	halted := false
+	interrupted := false // 取消或超时导致有步骤未执行
+	failed := -1         // 第一个失败的步骤
+
+	for {
+		if ctx.Err() != nil {
+			halted = true
+		}
+
+		// 启动依赖已全部成功的步骤，跳过无法再执行的步骤；跳过可能使后续步骤也无法执行，直到没有变化
+		for changed := true; changed; {
+			changed = false
+			for i, step := range steps {
+				if started[i] || done[i] {
+					continue
+				}
+
+				reason := ""
+				ready := true
+				for _, dep := range step.DependsOn {
+					j := index[dep]
+					if !done[j] {
+						ready = false
+					} else if logs[j].Status != models.LogStatusSuccess {
+						reason = fmt.Sprintf("skipped: dependency %s did not succeed", dep)
+					}
+				}
+				if halted {
+					reason = "skipped: workflow stopped"
+					if ctx.Err() != nil {
+						interrupted = true
+					}
+				}
+
+				switch {
+				case reason != "":
+					done[i] = true
+					logs[i] = skippedStep(step, reason)
+					changed = true
+				case ready:
+					stepRunID := uuid.New().String()
+					lane := !laneBusy
+					if !lane && !s.pool.tryAcquire(RunInfo{
+						RunID:    stepRunID,
+						TaskID:   task.ID,
+						TaskName: task.Name + " / " + step.ID,
+						QueuedAt: time.Now(),
+					}) {
+						continue
+					}
+					if lane {
+						laneBusy = true
+					}
+					started[i] = true
+					running++
+					go func(i int, step models.WorkflowStep, stepRunID string, lane bool) {
+						if !lane {
+							defer s.pool.release(stepRunID)
+						}
+						results <- stepResult{index: i, lane: lane, log: s.runStep(ctx, run, step, stepRunID, input)}
+					}(i, step, stepRunID, lane)
+				}
+			}
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		if result.lane {
+			laneBusy = false
+		}
+		done[result.index] = true
+		logs[result.index] = result.log
+		if result.log.Status != models.LogStatusSuccess {
+			log.Printf("Workflow %s: step %s %s", task.Name, result.log.Step, result.log.Status)
+			if failed < 0 {
+				failed = result.index
+			}
+			if policy != models.StepFailureContinue {
+				halted = true
+			}
+		}
+	}
+
+	// 失败后执行清理脚本，工作流已取消或超时也执行
+	if (failed >= 0 || interrupted) && policy == models.StepFailureCleanup {
+		cleanupCtx, cancelCleanup := context.WithCancel(context.Background())
+		defer cancelCleanup()
+		failedStep := ""
+		if failed >= 0 {
+			failedStep = steps[failed].ID
+		}
+		cleanupInput := executor.RunInput{Env: map[string]string{"TEMPO_FAILED_STEP": failedStep}, Args: input.Args}
+		for key, value := range input.Env {
+			cleanupInput.Env[key] = value
+		}
+		logs = append(logs, s.runStep(cleanupCtx, run, models.WorkflowStep{ID: cleanupStepID, ScriptID: wf.CleanupID}, uuid.New().String(), cleanupInput))
+	}
+
+	endTime := time.Now()
+	taskLog := &models.TaskLog{
+		ID:        runID,
+		TaskID:    task.ID,
+		TaskName:  task.Name,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Duration:  endTime.Sub(startTime).Milliseconds(),
+		Output:    workflowSummary(logs),
+		Steps:     logs,
+	}
+
+	switch {
+	case run.cancelled.Load() && (failed >= 0 || interrupted):
+		taskLog.Status = models.LogStatusCancelled
+		taskLog.Error = "cancelled by user"
+	case failed < 0 && interrupted:
+		taskLog.Status = models.LogStatusTimeout
+		taskLog.Error = "workflow timed out before all steps ran"
+	case failed < 0:
+		taskLog.Success = true
+		taskLog.Status = models.LogStatusSuccess
+	default:
+		first := logs[failed]
+		taskLog.Status = models.LogStatusFailed
+		if first.Status == models.LogStatusTimeout {
+			taskLog.Status = models.LogStatusTimeout
+		}
+		taskLog.Error = fmt.Sprintf("step %s %s: %s", first.Step, first.Status, first.Error)
+		taskLog.ExitCode = first.ExitCode
+	}
+	if cleanup := logs[len(logs)-1]; cleanup.Step == cleanupStepID && cleanup.Status != models.LogStatusSuccess {
+		taskLog.Error += fmt.Sprintf("; cleanup %s: %s", cleanup.Status, cleanup.Error)
+	}
+	return taskLog
+}
+
+// runStep 以 stepRunID 执行一个工作流步骤
+func (s *Scheduler) runStep(ctx context.Context, run *workflowRun, step models.WorkflowStep, stepRunID string, input executor.RunInput) models.StepLog {
+	stepLog := models.StepLog{
+		Step:      step.ID,
+		Name:      step.Name,
+		RunID:     stepRunID,
+		StartTime: time.Now(),
+	}
+
+	script, err := s.storage.GetScript(step.ScriptID)
+	if err != nil {
+		stepLog.EndTime = time.Now()
+		stepLog.Error = fmt.Sprintf("script not found: %s", step.ScriptID)
+		stepLog.Status = models.LogStatusFailed
+		stepLog.ExitCode = -1
+		return stepLog
+	}
+	if stepLog.Name == "" {
+		stepLog.Name = script.Name
+	}
+
+	timeout := time.Duration(step.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = s.storage.GetSettings().TimeoutFor(nil, script)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	env := map[string]string{
+		"TEMPO_WORKFLOW_RUN_ID": run.id,
+		"TEMPO_STEP_ID":         step.ID,
+	}
+	for key, value := range input.Env {
+		env[key] = value
+	}
+
+	log.Printf("Workflow %s: running step %s", run.taskName, step.ID)
+	result := s.executor.Execute(ctx, stepLog.RunID, script.ScriptType, script.ScriptPath, script.ScriptCode, executor.RunInput{
+		Env:        env,
+		Args:       input.Args,
+		ForwardTo:  run.id,
+		ForwardTag: step.ID,
+	})
+
+	stepLog.EndTime = time.Now()
+	stepLog.Duration = stepLog.EndTime.Sub(stepLog.StartTime).Milliseconds()
+	stepLog.Output = result.Output
+	stepLog.Error = result.Error
+	stepLog.Status = result.Status()
+	stepLog.ExitCode = result.ExitCode
+	s.touchScript(script)
+
+	// 整个工作流被取消时，被终止的步骤记为取消而不是失败
+	if run.cancelled.Load() && !result.Success && step.ID != cleanupStepID {
+		stepLog.Status = models.LogStatusCancelled
+		stepLog.Error = "cancelled by user"
+	}
+	return stepLog
+}
+
+// skippedStep 返回未执行步骤的日志
+func skippedStep(step models.WorkflowStep, reason string) models.StepLog {
+	now := time.Now()
+	return models.StepLog{
+		Step:      step.ID,
+		Name:      step.Name,
+		StartTime: now,
+		EndTime:   now,
+		Output:    reason,
+		Status:    models.LogStatusSkipped,
+	}
+}
+
+// workflowSummary 汇总各步骤的结果，每个步骤一行
+func workflowSummary(logs []models.StepLog) string {
+	var b strings.Builder
+	for _, stepLog := range logs {
+		fmt.Fprintf(&b, "[%s] %s", stepLog.Step, stepLog.Status)
+		if stepLog.Status != models.LogStatusSkipped {
+			fmt.Fprintf(&b, " (%s)", time.Duration(stepLog.Duration)*time.Millisecond)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// cancelWorkflow 取消运行中的工作流，运行中的步骤会被终止，其余步骤不再执行
+func (s *Scheduler) cancelWorkflow(runID string) bool {
+	s.workflowMu.Lock()
+	run, ok := s.workflows[runID]
+	s.workflowMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	run.cancelled.Store(true)
+	run.cancel()
+	return true
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"tempo/internal/executor"
+	"tempo/internal/models"
+	"tempo/internal/storage"
+)
+
+func TestWorkflowOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []models.WorkflowStep
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "independent keep definition order",
+			steps: []models.WorkflowStep{{ID: "b"}, {ID: "a"}, {ID: "c"}},
+			want:  "b,a,c",
+		},
+		{
+			name: "dependency defined later",
+			steps: []models.WorkflowStep{
+				{ID: "deploy", DependsOn: []string{"build", "test"}},
+				{ID: "test", DependsOn: []string{"build"}},
+				{ID: "build"},
+			},
+			want: "build,test,deploy",
+		},
+		{
+			name: "diamond",
+			steps: []models.WorkflowStep{
+				{ID: "a"},
+				{ID: "b", DependsOn: []string{"a"}},
+				{ID: "c", DependsOn: []string{"a"}},
+				{ID: "d", DependsOn: []string{"c", "b"}},
+			},
+			want: "a,b,c,d",
+		},
+		{
+			name:    "self dependency",
+			steps:   []models.WorkflowStep{{ID: "a"}, {ID: "b", DependsOn: []string{"b"}}},
+			wantErr: "cycle: b",
+		},
+		{
+			name: "cycle with dependents",
+			steps: []models.WorkflowStep{
+				{ID: "a"},
+				{ID: "b", DependsOn: []string{"a", "d"}},
+				{ID: "c", DependsOn: []string{"b"}},
+				{ID: "d", DependsOn: []string{"c"}},
+				{ID: "e", DependsOn: []string{"d"}},
+			},
+			wantErr: "cycle: b, c, d, e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := workflowOrder(tt.steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("workflowOrder error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("workflowOrder: %v", err)
+			}
+			if got := strings.Join(order, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWorkflowSkipPropagation(t *testing.T) {
+	dir := t.TempDir()
+	st, err := storage.New(dir)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	ex, err := executor.New(dir + "/scripts")
+	if err != nil {
+		t.Fatalf("executor.New: %v", err)
+	}
+	s := New(st, ex)
+	for id, code := range map[string]string{"ok": "exit 0", "fail": "exit 1"} {
+		if err := st.SaveScript(&models.Script{ID: id, Name: id, ScriptType: models.ScriptTypeShell, ScriptCode: code}); err != nil {
+			t.Fatalf("SaveScript: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		wf     models.Workflow
+		want   string // 各步骤状态，按定义顺序
+		status models.LogStatus
+	}{
+		{
+			name: "all succeed",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "ok"},
+				{ID: "b", ScriptID: "ok", DependsOn: []string{"a"}},
+			}},
+			want:   "a=success,b=success",
+			status: models.LogStatusSuccess,
+		},
+		{
+			name: "stop skips dependents transitively",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "fail"},
+				{ID: "b", ScriptID: "ok", DependsOn: []string{"a"}},
+				{ID: "c", ScriptID: "ok", DependsOn: []string{"b"}},
+			}},
+			want:   "a=failed,b=skipped,c=skipped",
+			status: models.LogStatusFailed,
+		},
+		{
+			name: "stop skips steps not yet started",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "ok"},
+				{ID: "b", ScriptID: "fail", DependsOn: []string{"a"}},
+				{ID: "c", ScriptID: "ok", DependsOn: []string{"b"}},
+			}, OnFailure: models.StepFailureStop},
+			want:   "a=success,b=failed,c=skipped",
+			status: models.LogStatusFailed,
+		},
+		{
+			name: "continue runs unrelated branch",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "fail"},
+				{ID: "b", ScriptID: "ok", DependsOn: []string{"a"}},
+				{ID: "c", ScriptID: "ok", DependsOn: []string{"b"}},
+				{ID: "d", ScriptID: "ok"},
+				{ID: "e", ScriptID: "ok", DependsOn: []string{"d"}},
+			}, OnFailure: models.StepFailureContinue},
+			want:   "a=failed,b=skipped,c=skipped,d=success,e=success",
+			status: models.LogStatusFailed,
+		},
+		{
+			name: "continue skips join of failed branch",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "ok"},
+				{ID: "b", ScriptID: "fail"},
+				{ID: "c", ScriptID: "ok", DependsOn: []string{"a", "b"}},
+			}, OnFailure: models.StepFailureContinue},
+			want:   "a=success,b=failed,c=skipped",
+			status: models.LogStatusFailed,
+		},
+		{
+			name: "cleanup after failure",
+			wf: models.Workflow{Steps: []models.WorkflowStep{
+				{ID: "a", ScriptID: "fail"},
+				{ID: "b", ScriptID: "ok", DependsOn: []string{"a"}},
+			}, OnFailure: models.StepFailureCleanup, CleanupID: "ok"},
+			want:   "a=failed,b=skipped,cleanup=success",
+			status: models.LogStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := tt.wf
+			taskLog := s.runWorkflow("run-"+strings.ReplaceAll(tt.name, " ", "-"), &models.Task{ID: "task", Name: "task", Workflow: &wf}, executor.RunInput{})
+
+			got := make([]string, len(taskLog.Steps))
+			for i, step := range taskLog.Steps {
+				got[i] = step.Step + "=" + string(step.Status)
+				if step.Status == models.LogStatusSkipped && !strings.HasPrefix(step.Output, "skipped: ") {
+					t.Errorf("step %s skipped without reason: %q", step.Step, step.Output)
+				}
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("steps = %s, want %s", strings.Join(got, ","), tt.want)
+			}
+			if taskLog.Status != tt.status || taskLog.Success != (tt.status == models.LogStatusSuccess) {
+				t.Errorf("status = %s (success %v), want %s", taskLog.Status, taskLog.Success, tt.status)
+			}
+		})
+	}
+}
@@ -7,16 +7,20 @@ import (
 	"path/filepath"
 	"sync"
 	"tempo/internal/models"
+	"time"
 )
 
 // Storage 存储接口
 type Storage struct {
-	dataDir string
-	mu      sync.RWMutex
-	scripts map[string]*models.Script
-	tasks   map[string]*models.Task
-	logs    map[string]*models.TaskLog
-	configs map[string]*models.NotifierConfig
+	dataDir   string
+	mu        sync.RWMutex
+	scripts   map[string]*models.Script
+	tasks     map[string]*models.Task
+	logs      map[string]*models.TaskLog
+	configs   map[string]*models.NotifierConfig
+	calendars map[string]*models.Calendar
+	settings  *models.Settings
+	state     *models.SchedulerState
 }
 
 // New 创建存储实例
@@ -26,11 +30,14 @@ func New(dataDir string) (*Storage, error) {
 	}
 
 	s := &Storage{
-		dataDir: dataDir,
-		scripts: make(map[string]*models.Script),
-		tasks:   make(map[string]*models.Task),
-		logs:    make(map[string]*models.TaskLog),
-		configs: make(map[string]*models.NotifierConfig),
+		dataDir:   dataDir,
+		scripts:   make(map[string]*models.Script),
+		tasks:     make(map[string]*models.Task),
+		logs:      make(map[string]*models.TaskLog),
+		configs:   make(map[string]*models.NotifierConfig),
+		calendars: make(map[string]*models.Calendar),
+		settings:  models.DefaultSettings(),
+		state:     &models.SchedulerState{},
 	}
 
 	if err := s.load(); err != nil {
@@ -54,6 +61,15 @@ func (s *Storage) load() error {
 	if err := s.loadConfigs(); err != nil {
 		return err
 	}
+	if err := s.loadCalendars(); err != nil {
+		return err
+	}
+	if err := s.loadSettings(); err != nil {
+		return err
+	}
+	if err := s.loadState(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -145,6 +161,69 @@ func (s *Storage) loadConfigs() error {
 	return nil
 }
 
+// loadCalendars 加载排除日历
+func (s *Storage) loadCalendars() error {
+	path := filepath.Join(s.dataDir, "calendars.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var calendars []*models.Calendar
+	if err := json.Unmarshal(data, &calendars); err != nil {
+		return err
+	}
+
+	for _, calendar := range calendars {
+		s.calendars[calendar.ID] = calendar
+	}
+	return nil
+}
+
+// loadSettings 加载全局设置
+func (s *Storage) loadSettings() error {
+	path := filepath.Join(s.dataDir, "settings.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// 在默认值基础上解析，兼容旧版本缺失的字段
+	settings := models.DefaultSettings()
+	if err := json.Unmarshal(data, settings); err != nil {
+		return err
+	}
+
+	s.settings = settings
+	return nil
+}
+
+// loadState 加载调度器状态
+func (s *Storage) loadState() error {
+	path := filepath.Join(s.dataDir, "state.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	state := &models.SchedulerState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
 // SaveScript 保存脚本
 func (s *Storage) SaveScript(script *models.Script) error {
 	s.mu.Lock()
@@ -238,6 +317,24 @@ func (s *Storage) SaveLog(log *models.TaskLog) error {
 	return s.saveLogs()
 }
 
+// DeleteLogsBefore 删除开始时间早于 cutoff 的日志，返回删除的数量
+func (s *Storage) DeleteLogsBefore(cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for id, log := range s.logs {
+		if log.StartTime.Before(cutoff) {
+			delete(s.logs, id)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.saveLogs()
+}
+
 // GetTaskLogs 获取任务日志
 func (s *Storage) GetTaskLogs(taskID string, limit int) []*models.TaskLog {
 	s.mu.RLock()
@@ -317,6 +414,86 @@ func (s *Storage) DeleteNotifierConfig(id string) error {
 	return s.saveConfigs()
 }
 
+// SaveCalendar 保存排除日历
+func (s *Storage) SaveCalendar(calendar *models.Calendar) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calendars[calendar.ID] = calendar
+	return s.saveCalendars()
+}
+
+// GetCalendar 获取排除日历
+func (s *Storage) GetCalendar(id string) (*models.Calendar, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	calendar, ok := s.calendars[id]
+	if !ok {
+		return nil, fmt.Errorf("calendar not found")
+	}
+	return calendar, nil
+}
+
+// GetAllCalendars 获取所有排除日历
+func (s *Storage) GetAllCalendars() []*models.Calendar {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	calendars := make([]*models.Calendar, 0, len(s.calendars))
+	for _, calendar := range s.calendars {
+		calendars = append(calendars, calendar)
+	}
+	return calendars
+}
+
+// DeleteCalendar 删除排除日历
+func (s *Storage) DeleteCalendar(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.calendars, id)
+	return s.saveCalendars()
+}
+
+// GetSettings 获取全局设置（返回副本）
+func (s *Storage) GetSettings() *models.Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := *s.settings
+	return &settings
+}
+
+// SaveSettings 保存全局设置
+func (s *Storage) SaveSettings(settings *models.Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *settings
+	s.settings = &saved
+	return s.saveSettings()
+}
+
+// GetSchedulerState 获取调度器状态（返回副本）
+func (s *Storage) GetSchedulerState() *models.SchedulerState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := *s.state
+	return &state
+}
+
+// SaveSchedulerState 保存调度器状态
+func (s *Storage) SaveSchedulerState(state *models.SchedulerState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *state
+	s.state = &saved
+	return s.saveState()
+}
+
 // saveScripts 保存脚本到文件
 func (s *Storage) saveScripts() error {
 	scripts := make([]*models.Script, 0, len(s.scripts))
@@ -380,3 +557,41 @@ func (s *Storage) saveConfigs() error {
 	path := filepath.Join(s.dataDir, "configs.json")
 	return os.WriteFile(path, data, 0644)
 }
+
+// saveCalendars 保存排除日历到文件
+func (s *Storage) saveCalendars() error {
+	calendars := make([]*models.Calendar, 0, len(s.calendars))
+	for _, calendar := range s.calendars {
+		calendars = append(calendars, calendar)
+	}
+
+	data, err := json.MarshalIndent(calendars, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dataDir, "calendars.json")
+	return os.WriteFile(path, data, 0644)
+}
+
+// saveSettings 保存全局设置到文件
+func (s *Storage) saveSettings() error {
+	data, err := json.MarshalIndent(s.settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dataDir, "settings.json")
+	return os.WriteFile(path, data, 0644)
+}
+
+// saveState 保存调度器状态到文件
+func (s *Storage) saveState() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dataDir, "state.json")
+	return os.WriteFile(path, data, 0644)
+}
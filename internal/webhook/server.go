@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"tempo/internal/models"
+	"tempo/internal/scheduler"
+	"tempo/internal/storage"
+	"time"
+)
+
+// maxBodySize 请求体大小上限
+const maxBodySize = 1 << 20
+
+// envNameInvalid 环境变量名中不允许的字符
+var envNameInvalid = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// Server 接收 Webhook 请求并触发任务的 HTTP 服务
+type Server struct {
+	storage   *storage.Storage
+	scheduler *scheduler.Scheduler
+
+	mu     sync.Mutex
+	server *http.Server
+	addr   string
+}
+
+// New 创建 Webhook 服务
+func New(storage *storage.Storage, scheduler *scheduler.Scheduler) *Server {
+	return &Server{
+		storage:   storage,
+		scheduler: scheduler,
+	}
+}
+
+// NewToken 生成随机的触发密钥
+func NewToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate webhook token: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// Start 在 addr 上开始监听，已在监听时先停止
+func (s *Server) Start(addr string) error {
+	s.Stop()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /hooks/{id}", s.handleTrigger)
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.mu.Lock()
+	s.server = server
+	s.addr = listener.Addr().String()
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Webhook server error: %v", err)
+		}
+	}()
+	log.Printf("Webhook server listening on %s", s.addr)
+	return nil
+}
+
+// Stop 停止监听
+func (s *Server) Stop() {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.addr = ""
+	s.mu.Unlock()
+
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to stop webhook server: %v", err)
+	}
+	log.Println("Webhook server stopped")
+}
+
+// Addr 返回实际监听的地址，未监听时返回空字符串
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// URL 返回任务的触发地址
+func URL(addr string, task *models.Task) string {
+	return fmt.Sprintf("http://%s/hooks/%s?token=%s", addr, task.ID, task.Webhook.Token)
+}
+
+// handleTrigger 校验密钥后运行任务
+// 请求体写入临时文件，路径通过 TEMPO_INPUT_FILE 传给脚本；查询参数以 TEMPO_QUERY_<NAME> 环境变量传入
+func (s *Server) handleTrigger(w http.ResponseWriter, r *http.Request) {
+	task, err := s.storage.GetTask(r.PathValue("id"))
+	if err != nil || !task.Webhook.Enabled || task.Webhook.Token == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "task not found"})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(requestToken(r)), []byte(task.Webhook.Token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		return
+	}
+
+	if task.Status != models.TaskStatusActive {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "task is not active"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "request body too large"})
+		return
+	}
+
+	query := r.URL.Query()
+	query.Del("token")
+	env := map[string]string{
+		"TEMPO_TRIGGER":              string(models.TriggerWebhook),
+		"TEMPO_WEBHOOK_QUERY":        query.Encode(),
+		"TEMPO_WEBHOOK_CONTENT_TYPE": r.Header.Get("Content-Type"),
+	}
+	for name, values := range query {
+		key := "TEMPO_QUERY_" + envNameInvalid.ReplaceAllString(strings.ToUpper(name), "_")
+		env[key] = strings.Join(values, ",")
+	}
+
+	if err := s.scheduler.RunTask(task.ID, scheduler.RunOptions{
+		Trigger: models.TriggerWebhook,
+		Info:    "webhook from " + remoteHost(r),
+		Env:     env,
+		Input:   body,
+	}); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	log.Printf("Task %s triggered by webhook from %s", task.Name, remoteHost(r))
+	writeJSON(w, http.StatusAccepted, map[string]string{"taskId": task.ID, "status": "accepted"})
+}
+
+// requestToken 从查询参数、X-Tempo-Token 或 Bearer 认证头中读取密钥
+func requestToken(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	if token := r.Header.Get("X-Tempo-Token"); token != "" {
+		return token
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// remoteHost 返回请求来源地址
+func remoteHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
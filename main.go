@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	_ "time/tzdata" // 内置时区数据库，Windows 等系统缺少 zoneinfo 时也能解析任务时区
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"